@@ -77,7 +77,7 @@ export class Err<T extends string> extends Error {
     }
 }
 
-type QueryOperator = 'EQ' | 'NE' | 'LT' | 'LE' | 'GT' | 'GE' | 'IN' | 'AND' | 'OR' | 'NOT'
+type QueryOperator = 'EQ' | 'NE' | 'LT' | 'LE' | 'GT' | 'GE' | 'IN' | 'AND' | 'OR' | 'NOT' | 'FTS'
 
 export interface QueryVal {
     __yoQLitValue?: any,
@@ -115,6 +115,7 @@ export class QVal<T extends (string | number | boolean | null)>  {
     greaterThan(other: QueryVal): QueryExpr { return qGreaterThan(this, other) }
     greaterOrEqual(other: QueryVal): QueryExpr { return qGreaterOrEqual(this, other) }
     in(...set: QueryVal[]): QueryExpr { return qIn(this, ...set) }
+    fullTextMatch(): QueryExpr { return qFullTextMatch(this as unknown as QVal<string>) } // only for `FullText` types, with `this` being the search terms
     toApiQueryExpr(): object | null {
         if (typeof this.__yoQLitValue === 'string')
             return { 'Str': this.__yoQLitValue ?? '' }
@@ -150,3 +151,4 @@ function qLessOrEqual(x: QueryVal, y: QueryVal): QueryExpr { return { __yoQOp: '
 function qGreaterThan(x: QueryVal, y: QueryVal): QueryExpr { return { __yoQOp: 'GT', __yoQOperands: [x, y] } as QueryExpr }
 function qGreaterOrEqual(x: QueryVal, y: QueryVal): QueryExpr { return { __yoQOp: 'GE', __yoQOperands: [x, y] } as QueryExpr }
 function qIn(x: QueryVal, ...set: QueryVal[]): QueryExpr { return { __yoQOp: 'IN', __yoQOperands: [x].concat(set) } as QueryExpr }
+function qFullTextMatch(searchTerms: QVal<string>): QueryExpr { return { __yoQOp: 'FTS', __yoQOperands: [searchTerms] } as QueryExpr }
@@ -25,6 +25,10 @@ var (
 )
 
 func init() {
+	yodb.AuditAccountId = func(ctx *Ctx) yodb.I64 {
+		account_id, _ := ctx.Get(CtxKeyAccountId, yodb.I64(0)).(yodb.I64)
+		return account_id
+	}
 	if IsDevMode {
 		Apis(ApiMethods{
 			MethodPathLogout: api(ApiUserLogout),
@@ -149,6 +153,15 @@ func CurrentlyLoggedInUser(ctx *Ctx) (emailAddr string, authID yodb.I64) {
 	return ctx.GetStr(CtxKeyEmailAddr), ctx.Get(CtxKeyAccountId, yodb.I64(0)).(yodb.I64)
 }
 
+// RateLimitByAccount is a `RateLimit.Key` for the currently logged-in user's account, else (if not logged in) the client IP.
+// The `RateLimiting` middleware using it must come after the "authCheck" in `PreServes` (or be in `PreApiHandling`).
+func RateLimitByAccount(ctx *Ctx) string {
+	if _, account_id := CurrentlyLoggedInUser(ctx); account_id > 0 {
+		return "account:" + str.FromI64(int64(account_id), 10)
+	}
+	return "ip:" + RateLimitByIp(ctx)
+}
+
 func IsCurrentlyLoggedIn(ctx *Ctx) bool {
 	user_email_addr, account_id := CurrentlyLoggedInUser(ctx)
 	return (user_email_addr != "") && (account_id > 0)
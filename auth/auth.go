@@ -78,21 +78,25 @@ func UserPregisterOrForgotPassword(ctx *Ctx, emailAddr string) {
 }
 
 func UserRegister(ctx *Ctx, emailAddr string, passwordPlain string) (ret yodb.I64) {
-	ctx.DbTx(true)
-
-	if IsDevMode && yodb.Exists[UserAccount](ctx, UserAccountEmailAddr.Equal(emailAddr)) {
-		// this branch never taken in prod to help prevent time-based-attacks.
-		// the DB-side unique constraint will still fail the insert attempt (and genericized in prod, see below).
-		panic(Err___yo_authRegister_EmailAddrAlreadyExists)
-	}
+	pwd_hashed := pwdHashStorable(passwordPlain, emailAddr)
+	yodb.InTx(ctx, yodb.TxOpts{Serious: true}, func(ctx *Ctx) {
+		if IsDevMode && yodb.Exists[UserAccount](ctx, UserAccountEmailAddr.Equal(emailAddr)) {
+			// this branch never taken in prod to help prevent time-based-attacks.
+			// the DB-side unique constraint will still fail the insert attempt (and genericized in prod, see below).
+			panic(Err___yo_authRegister_EmailAddrAlreadyExists)
+		}
 
-	Try(func() {
-		ret = yodb.I64(yodb.CreateOne[UserAccount](ctx, &UserAccount{
-			EmailAddr: yodb.Text(emailAddr),
-			pwdHashed: pwdHashStorable(passwordPlain, emailAddr),
-		}))
-	}, func(err any) {
-		panic(If[any](EnforceGenericizedErrors, err, errGeneric))
+		Try(func() {
+			ret = yodb.I64(yodb.CreateOne[UserAccount](ctx, &UserAccount{
+				EmailAddr: yodb.Text(emailAddr),
+				pwdHashed: pwd_hashed,
+			}))
+		}, func(err any) {
+			if err == ErrDbUnique_UserAccount_EmailAddr {
+				err = Err___yo_authRegister_EmailAddrAlreadyExists
+			}
+			panic(If[any](EnforceGenericizedErrors || yodb.TxRetryable(err), err, errGeneric))
+		})
 	})
 	return
 }
@@ -153,24 +157,27 @@ func UserLoginOrFinalizeRegisterOrPwdReset(ctx *Ctx, emailAddr string, passwordP
 		panic(Err___yo_authLoginOrFinalizePwdReset_WrongPassword)
 	}
 	pwd_hash := pwdHashStorable(password2Plain, emailAddr)
-	ctx.DbTx(true)
-	account := yodb.FindOne[UserAccount](ctx, UserAccountEmailAddr.Equal(emailAddr))
-	if account != nil { // existing user: pwd-reset
-		account.pwdHashed, account.FailedLoginAttempts, account.Lockout = pwd_hash, nil, false
-		_ = yodb.Update[UserAccount](ctx, account, nil, true, UserAccountFields(userAccountPwdHashed, UserAccountFailedLoginAttempts, UserAccountLockout)...)
-	} else { // new user: register
-		account = &UserAccount{pwdHashed: pwd_hash, EmailAddr: yodb.Text(emailAddr)}
-		account.Id = yodb.CreateOne[UserAccount](ctx, account)
-	}
-	pwd_reset_req.tmpPwdHashed = nil
-	if yodb.Update(ctx, pwd_reset_req, nil, false, UserPwdReqFields(userPwdReqTmpPwdHashed)...) < 0 {
-		panic(ErrDbUpdate_ExpectedChangesForUpdate)
-	}
-	if AutoLoginAfterSuccessfullyFinalizedSignUpOrPwdResetReq {
-		return UserLogin(ctx, emailAddr, password2Plain)
-	}
-
-	return account, nil
+	var account *UserAccount
+	var jwt_token *jwt.Token
+	yodb.InTx(ctx, yodb.TxOpts{Serious: true}, func(ctx *Ctx) {
+		account = yodb.FindOne[UserAccount](ctx, UserAccountEmailAddr.Equal(emailAddr))
+		if account != nil { // existing user: pwd-reset
+			account.pwdHashed, account.FailedLoginAttempts, account.Lockout = pwd_hash, nil, false
+			_ = yodb.Update[UserAccount](ctx, account, nil, true, UserAccountFields(userAccountPwdHashed, UserAccountFailedLoginAttempts, UserAccountLockout)...)
+		} else { // new user: register
+			account = &UserAccount{pwdHashed: pwd_hash, EmailAddr: yodb.Text(emailAddr)}
+			account.Id = yodb.CreateOne[UserAccount](ctx, account)
+		}
+		pwd_reset_req := *pwd_reset_req // not the original, so that retries start out from the same state
+		pwd_reset_req.tmpPwdHashed = nil
+		if yodb.Update(ctx, &pwd_reset_req, nil, false, UserPwdReqFields(userPwdReqTmpPwdHashed)...) < 0 {
+			panic(ErrDbUpdate_ExpectedChangesForUpdate)
+		}
+		if AutoLoginAfterSuccessfullyFinalizedSignUpOrPwdResetReq {
+			account, jwt_token = UserLogin(ctx, emailAddr, password2Plain)
+		}
+	})
+	return account, jwt_token
 }
 
 func UserVerify(jwtRaw string) *JwtPayload {
@@ -186,11 +193,12 @@ func UserVerify(jwtRaw string) *JwtPayload {
 }
 
 func UserChangePassword(ctx *Ctx, emailAddr string, passwordOldPlain string, passwordNewPlain string) {
-	ctx.DbTx(true)
-	user_account, _ := UserLogin(ctx, emailAddr, passwordOldPlain)
 	hash := pwdHashStorable(passwordNewPlain, emailAddr)
-	user_account.pwdHashed, user_account.FailedLoginAttempts, user_account.Lockout = hash, nil, false
-	_ = yodb.Update[UserAccount](ctx, user_account, nil, true, UserAccountFields(userAccountPwdHashed, UserAccountFailedLoginAttempts, UserAccountLockout)...)
+	yodb.InTx(ctx, yodb.TxOpts{Serious: true}, func(ctx *Ctx) {
+		user_account, _ := UserLogin(ctx, emailAddr, passwordOldPlain)
+		user_account.pwdHashed, user_account.FailedLoginAttempts, user_account.Lockout = hash, nil, false
+		_ = yodb.Update[UserAccount](ctx, user_account, nil, true, UserAccountFields(userAccountPwdHashed, UserAccountFailedLoginAttempts, UserAccountLockout)...)
+	})
 }
 
 func ById(ctx *Ctx, id yodb.I64) *UserAccount {
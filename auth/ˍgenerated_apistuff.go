@@ -18,7 +18,9 @@ func api[TIn any, TOut any](f func(*yosrv.ApiCtx[TIn, TOut]), failIfs ...yosrv.F
 	return yosrv.Api[TIn, TOut](f, failIfs...).From(yoauthPkg)
 }
 
+const ErrDbUpdate_Conflict util.Err = "DbUpdate_Conflict"
 const ErrDbUpdate_ExpectedChangesForUpdate util.Err = "DbUpdate_ExpectedChangesForUpdate"
+const ErrDbUpdate_ExpectedNoQueryForVersionedUpdate util.Err = "DbUpdate_ExpectedNoQueryForVersionedUpdate"
 const ErrDbUpdate_ExpectedQueryForUpdate util.Err = "DbUpdate_ExpectedQueryForUpdate"
 const Err___yo_authChangePassword_NewPasswordExpectedToDiffer util.Err = "___yo_authChangePassword_NewPasswordExpectedToDiffer"
 const Err___yo_authChangePassword_NewPasswordTooShort util.Err = "___yo_authChangePassword_NewPasswordTooShort"
@@ -36,6 +38,12 @@ const Err___yo_authRegister_EmailInvalid util.Err = "___yo_authRegister_EmailInv
 const Err___yo_authRegister_EmailRequiredButMissing util.Err = "___yo_authRegister_EmailRequiredButMissing"
 const Err___yo_authRegister_PasswordTooLong util.Err = "___yo_authRegister_PasswordTooLong"
 const Err___yo_authRegister_PasswordTooShort util.Err = "___yo_authRegister_PasswordTooShort"
+const ErrDbNotNull_UserAccount_EmailAddr util.Err = "DbNotNull_UserAccount_EmailAddr"
+const ErrDbNotNull_UserAccount_Lockout util.Err = "DbNotNull_UserAccount_Lockout"
+const ErrDbNotNull_UserPwdReq_EmailAddr util.Err = "DbNotNull_UserPwdReq_EmailAddr"
+const ErrDbRef_UserPwdReq_DoneMailReqId util.Err = "DbRef_UserPwdReq_DoneMailReqId"
+const ErrDbUnique_UserAccount_EmailAddr util.Err = "DbUnique_UserAccount_EmailAddr"
+const ErrDbUnique_UserPwdReq_EmailAddr util.Err = "DbUnique_UserPwdReq_EmailAddr"
 const ___yo_authChangePasswordEmailAddr = q.F("EmailAddr")
 const ___yo_authChangePasswordPassword2Plain = q.F("Password2Plain")
 const ___yo_authChangePasswordPasswordPlain = q.F("PasswordPlain")
@@ -30,16 +30,19 @@ func (me UserAccountField) F() q.F { return ((q.F)(me)).F() }
 func (me UserAccountField) GreaterOrEqual(a1 interface{}) q.Query {
 	return ((q.F)(me)).GreaterOrEqual(a1)
 }
-func (me UserAccountField) GreaterThan(a1 interface{}) q.Query { return ((q.F)(me)).GreaterThan(a1) }
-func (me UserAccountField) In(a1 ...interface{}) q.Query       { return ((q.F)(me)).In(a1...) }
-func (me UserAccountField) InArr(a1 interface{}) q.Query       { return ((q.F)(me)).InArr(a1) }
-func (me UserAccountField) LessOrEqual(a1 interface{}) q.Query { return ((q.F)(me)).LessOrEqual(a1) }
-func (me UserAccountField) LessThan(a1 interface{}) q.Query    { return ((q.F)(me)).LessThan(a1) }
-func (me UserAccountField) Not() q.Query                       { return ((q.F)(me)).Not() }
-func (me UserAccountField) NotEqual(a1 interface{}) q.Query    { return ((q.F)(me)).NotEqual(a1) }
-func (me UserAccountField) NotIn(a1 ...interface{}) q.Query    { return ((q.F)(me)).NotIn(a1...) }
-func (me UserAccountField) NotInArr(a1 interface{}) q.Query    { return ((q.F)(me)).NotInArr(a1) }
-func (me UserAccountField) StrLen(a1 ...interface{}) q.Operand { return ((q.F)(me)).StrLen(a1...) }
+func (me UserAccountField) GreaterThan(a1 interface{}) q.Query   { return ((q.F)(me)).GreaterThan(a1) }
+func (me UserAccountField) In(a1 ...interface{}) q.Query         { return ((q.F)(me)).In(a1...) }
+func (me UserAccountField) InArr(a1 interface{}) q.Query         { return ((q.F)(me)).InArr(a1) }
+func (me UserAccountField) JsonContains(a1 interface{}) q.Query  { return ((q.F)(me)).JsonContains(a1) }
+func (me UserAccountField) JsonHasKey(a1 string) q.Query         { return ((q.F)(me)).JsonHasKey(a1) }
+func (me UserAccountField) JsonPath(a1 ...interface{}) q.Operand { return ((q.F)(me)).JsonPath(a1...) }
+func (me UserAccountField) LessOrEqual(a1 interface{}) q.Query   { return ((q.F)(me)).LessOrEqual(a1) }
+func (me UserAccountField) LessThan(a1 interface{}) q.Query      { return ((q.F)(me)).LessThan(a1) }
+func (me UserAccountField) Not() q.Query                         { return ((q.F)(me)).Not() }
+func (me UserAccountField) NotEqual(a1 interface{}) q.Query      { return ((q.F)(me)).NotEqual(a1) }
+func (me UserAccountField) NotIn(a1 ...interface{}) q.Query      { return ((q.F)(me)).NotIn(a1...) }
+func (me UserAccountField) NotInArr(a1 interface{}) q.Query      { return ((q.F)(me)).NotInArr(a1) }
+func (me UserAccountField) StrLen(a1 ...interface{}) q.Operand   { return ((q.F)(me)).StrLen(a1...) }
 
 func UserPwdReqFields(fields ...UserPwdReqField) []q.F { return sl.As(fields, UserPwdReqField.F) }
 
@@ -72,13 +75,16 @@ func (me UserPwdReqField) F() q.F { return ((q.F)(me)).F() }
 func (me UserPwdReqField) GreaterOrEqual(a1 interface{}) q.Query {
 	return ((q.F)(me)).GreaterOrEqual(a1)
 }
-func (me UserPwdReqField) GreaterThan(a1 interface{}) q.Query { return ((q.F)(me)).GreaterThan(a1) }
-func (me UserPwdReqField) In(a1 ...interface{}) q.Query       { return ((q.F)(me)).In(a1...) }
-func (me UserPwdReqField) InArr(a1 interface{}) q.Query       { return ((q.F)(me)).InArr(a1) }
-func (me UserPwdReqField) LessOrEqual(a1 interface{}) q.Query { return ((q.F)(me)).LessOrEqual(a1) }
-func (me UserPwdReqField) LessThan(a1 interface{}) q.Query    { return ((q.F)(me)).LessThan(a1) }
-func (me UserPwdReqField) Not() q.Query                       { return ((q.F)(me)).Not() }
-func (me UserPwdReqField) NotEqual(a1 interface{}) q.Query    { return ((q.F)(me)).NotEqual(a1) }
-func (me UserPwdReqField) NotIn(a1 ...interface{}) q.Query    { return ((q.F)(me)).NotIn(a1...) }
-func (me UserPwdReqField) NotInArr(a1 interface{}) q.Query    { return ((q.F)(me)).NotInArr(a1) }
-func (me UserPwdReqField) StrLen(a1 ...interface{}) q.Operand { return ((q.F)(me)).StrLen(a1...) }
+func (me UserPwdReqField) GreaterThan(a1 interface{}) q.Query   { return ((q.F)(me)).GreaterThan(a1) }
+func (me UserPwdReqField) In(a1 ...interface{}) q.Query         { return ((q.F)(me)).In(a1...) }
+func (me UserPwdReqField) InArr(a1 interface{}) q.Query         { return ((q.F)(me)).InArr(a1) }
+func (me UserPwdReqField) JsonContains(a1 interface{}) q.Query  { return ((q.F)(me)).JsonContains(a1) }
+func (me UserPwdReqField) JsonHasKey(a1 string) q.Query         { return ((q.F)(me)).JsonHasKey(a1) }
+func (me UserPwdReqField) JsonPath(a1 ...interface{}) q.Operand { return ((q.F)(me)).JsonPath(a1...) }
+func (me UserPwdReqField) LessOrEqual(a1 interface{}) q.Query   { return ((q.F)(me)).LessOrEqual(a1) }
+func (me UserPwdReqField) LessThan(a1 interface{}) q.Query      { return ((q.F)(me)).LessThan(a1) }
+func (me UserPwdReqField) Not() q.Query                         { return ((q.F)(me)).Not() }
+func (me UserPwdReqField) NotEqual(a1 interface{}) q.Query      { return ((q.F)(me)).NotEqual(a1) }
+func (me UserPwdReqField) NotIn(a1 ...interface{}) q.Query      { return ((q.F)(me)).NotIn(a1...) }
+func (me UserPwdReqField) NotInArr(a1 interface{}) q.Query      { return ((q.F)(me)).NotInArr(a1) }
+func (me UserPwdReqField) StrLen(a1 ...interface{}) q.Operand   { return ((q.F)(me)).StrLen(a1...) }
@@ -17,11 +17,23 @@ var Cfg struct { // all renames MUST be global find+replace!
 	YO_APP_DOMAIN string
 
 	YO_API_HTTP_PORT                   int
+	YO_API_HTTPS_PORT                  int    // only used if `YO_API_TLS_CERT_FILE` is set
+	YO_API_HTTP_REDIRECT_TO_HTTPS      bool   // if TLS: whether `YO_API_HTTP_PORT` only redirects to HTTPS (except health checks)
+	YO_API_HTTP2_H2C                   bool   // whether plain-HTTP serves unencrypted HTTP/2, for behind proxies (HTTPS always has HTTP/2)
+	YO_API_TLS_CERT_FILE               string // both files get re-loaded on changes, no restart needed
+	YO_API_TLS_KEY_FILE                string
 	YO_API_IMPL_TIMEOUT                time.Duration
+	YO_API_EXPORT_TIMEOUT              time.Duration // if set, how long `yodb.ExportToHttp` downloads may stream (instead of YO_API_IMPL_TIMEOUT)
 	YO_API_MAX_REQ_CONTENTLENGTH_MB    int
 	YO_API_MAX_REQ_MULTIPART_LENGTH_MB int
 	YO_API_ADMIN_USER                  string
 	YO_API_ADMIN_PWD                   string
+	YO_API_SHUTDOWN_DRAIN_TIMEOUT      time.Duration // on SIGTERM/SIGINT, how long to let in-flight requests and job tasks finish (22s if unset)
+	YO_API_CORS_ALLOW_ORIGINS          []string      // exact origins, or with a sub-domain wildcard like `https://*.foo.com`, or `*` (the default if unset)
+	YO_API_CORS_ALLOW_CREDENTIALS      bool          // needed for cookie-based auth from other origins, not allowed with `*`
+	YO_API_CORS_ALLOW_HEADERS          []string      // request headers allowed for preflights, `Content-Type` if unset
+	YO_API_CORS_MAX_AGE                time.Duration // how long browsers may cache preflight responses
+	YO_API_RESP_GZIP_MIN_BYTES         int           // API responses at least this large get gzipped for accepting clients (1024 if unset, -1 disables)
 
 	YO_AUTH_JWT_COOKIE_NAME        string
 	YO_AUTH_JWT_COOKIE_EXPIRY_DAYS int
@@ -38,8 +50,14 @@ var Cfg struct { // all renames MUST be global find+replace!
 	YO_MAIL_SMTP_TIMEOUT   time.Duration
 	YO_MAIL_ERR_LOG_FWD_TO string
 
-	YO_DB_CONN_TIMEOUT time.Duration
-	YO_DB_CONN_URL     string
+	YO_DB_CONN_TIMEOUT         time.Duration
+	YO_DB_CONN_URL             string
+	YO_DB_CONN_URL_READONLY    []string
+	YO_DB_MIG_DRY_RUN          bool
+	YO_DB_PAGE_TOK_SIGN_KEY    string
+	YO_DB_TX_MAX_ATTEMPTS      int
+	YO_DB_SLOW_QUERY_THRESHOLD time.Duration // if > 0, queries taking at least this long get logged
+	YO_DB_SLOW_QUERY_EXPLAIN   bool          // if true, slow reads get sent to the DB again as plain `EXPLAIN`s (planned again but, without ANALYZE, not executed again) for the log, unless `Ctx.Db.ExplainAnalyze`
 
 	STATIC_FILE_STORAGE_DIRS map[string]string
 }
@@ -55,7 +73,7 @@ func init() {
 
 	// Setenv from .env file if any
 	is_local_prod, is_env_prod := (os.Getenv("YO_LOCAL") != ""), false
-	local_skip_env_names := []string{"YO_API_HTTP_PORT", "YO_DB_CONN_URL", "STATIC_FILE_STORAGE_DIRS"}
+	local_skip_env_names := []string{"YO_API_HTTP_PORT", "YO_API_HTTPS_PORT", "YO_API_TLS_CERT_FILE", "YO_API_TLS_KEY_FILE", "YO_DB_CONN_URL", "YO_DB_CONN_URL_READONLY", "STATIC_FILE_STORAGE_DIRS"}
 	for _, file_name := range []string{".env", ".env.prod"} /* note, keep this slice order */ {
 		if env_file_data := bytes.TrimSpace(FsRead(file_name)); len(env_file_data) > 0 {
 			for i, lines := 0, str.Split(string(env_file_data), "\n"); i < len(lines); i++ {
@@ -53,6 +53,10 @@ type Ctx struct {
 	Db   struct {
 		PrintRawSqlInDevMode bool // never printed in non-dev-mode anyway
 		Tx                   *sql.Tx
+		ReadYourWrites       bool // if true, reads never go to `YO_DB_CONN_URL_READONLY` replicas. Set automatically by any write via this `Ctx`
+		Stats                DbStats
+		ExplainAnalyze       bool // if true, `yodb` runs each query again as `EXPLAIN (ANALYZE, BUFFERS)` in an always-rolled-back TX (or SAVEPOINT), see `DbStats.Explained`
+		savepoints           int
 	}
 	Timings                 Timings
 	TimingsNoPrintInDevMode bool // never printed in non-dev-mode anyway
@@ -61,6 +65,32 @@ type Ctx struct {
 	ErrNoNotifyOf           []Err
 }
 
+// DbStats are recorded by `yodb` for all queries via a `Ctx`, added to its `Timings` in `OnDone` and reported with `ErrEntry`s.
+type DbStats struct {
+	NumQueries  int
+	NumRows     int64
+	Duration    time.Duration
+	SlowQueries []string // those exceeding `YO_DB_SLOW_QUERY_THRESHOLD`, with args redacted and any `YO_DB_SLOW_QUERY_EXPLAIN` output
+	Explained   []string // the `EXPLAIN (ANALYZE, BUFFERS)` outputs of queries run while `Ctx.Db.ExplainAnalyze`
+}
+
+func (me *DbStats) String() (ret string) {
+	ret = str.Fmt("db: %d queries, %d rows, %s", me.NumQueries, me.NumRows, str.DurationMs(int64(me.Duration)))
+	for _, slow_query := range me.SlowQueries {
+		ret += "\n" + slow_query
+	}
+	for _, explained := range me.Explained {
+		ret += "\n" + explained
+	}
+	return
+}
+
+// Add merges `other` into `me`, such as the `DbStats` of a `CopyButWith` copy back into those of its origin.
+func (me *DbStats) Add(other *DbStats) {
+	me.NumQueries, me.NumRows, me.Duration = me.NumQueries+other.NumQueries, me.NumRows+other.NumRows, me.Duration+other.Duration
+	me.SlowQueries, me.Explained = append(me.SlowQueries, other.SlowQueries...), append(me.Explained, other.Explained...)
+}
+
 type ctxHttp struct {
 	Req         *http.Request
 	Resp        http.ResponseWriter
@@ -114,7 +144,7 @@ func (me *Ctx) WithJob(jobRunId int64, jobRunDetails any, jobTaskId int64) *Ctx
 
 func (me *Ctx) CopyButWith(timeout time.Duration, cancelable bool) *Ctx {
 	ret := *me
-	ret.Db.Tx, ret.Context, ret.ctxDone = nil, context.Background(), nil
+	ret.Db.Tx, ret.Db.Stats, ret.Db.savepoints, ret.Context, ret.ctxDone = nil, DbStats{}, 0, context.Background(), nil
 	if timeout > 0 {
 		ret.Context, ret.ctxDone = context.WithTimeout(ret.Context, timeout)
 	} else if dt_deadline, has := me.Context.Deadline(); has && (timeout < 0) {
@@ -164,9 +194,12 @@ func (me *Ctx) OnDone(alsoDo func()) (fail any) {
 			_ = me.Db.Tx.Rollback() // this potential-err really can be ignored, never-committed txs are goners afaik. plus consider the case of commit-successful-at-db but conn-reset/timeout in between that and our Commit() call finally returning. we'll be in this branch, Rollback errs just with "already commit/rollback-ed". no action item other than what we anyway do below here.
 		}
 	}
+	abort_resp := false
 	if me.Http != nil {
 		me.httpEnsureCookiesSent()
-		if code := 500; fail != nil {
+		if code := 500; (fail != nil) && me.Http.respWriting {
+			abort_resp = true // too late for an error status, so rather than appending the error text to a partial response, cut it off
+		} else if fail != nil {
 			if err, is_app_err := fail.(Err); is_app_err {
 				if IsDevMode && me.Http.ApiMethod != nil {
 					if known_errs := me.Http.ApiMethod.KnownErrs(false); (len(known_errs) > 0) && (err != ErrMustBeAdmin) && !sl.Has(known_errs, err) {
@@ -204,6 +237,9 @@ func (me *Ctx) OnDone(alsoDo func()) (fail any) {
 			go NotifyErrCaught(me, me.ctxVals, fail, stack_trace)
 		}
 	}
+	if me.Db.Stats.NumQueries > 0 {
+		me.Timings.Step(me.Db.Stats.String())
+	}
 	if (IsDevMode || !IsUp) && !me.TimingsNoPrintInDevMode {
 		total_duration, steps := me.Timings.AllDone()
 		println("\n" + me.Timings.String() + "\n  . . . " + str.DurationMs(total_duration) + ", like so:")
@@ -211,6 +247,9 @@ func (me *Ctx) OnDone(alsoDo func()) (fail any) {
 			println(step.Step + ":\t" + str.DurationMs(step.Time))
 		}
 	}
+	if abort_resp {
+		panic(http.ErrAbortHandler)
+	}
 	return
 }
 
@@ -247,7 +286,7 @@ func (me *Ctx) Set(name string, value any) {
 }
 
 func (me *Ctx) DbTx(serious bool) {
-	if me.Db.Tx != nil {
+	if (me.Db.Tx != nil) || (DB == nil) { // no `DB` with `yodb.InitInMem`, whose changes are never rolled back
 		return
 	}
 	var err error
@@ -256,6 +295,32 @@ func (me *Ctx) DbTx(serious bool) {
 	}
 }
 
+// DbTxSub runs `do` in a nested scope (a SAVEPOINT) of the current DB transaction, first begun via `DbTx(false)` if none yet.
+// If `do` panics, only its own DB changes are rolled back and its panic is returned, so that the outer transaction
+// (committed in `OnDone`) remains usable. Scopes can nest. (With `yodb.InitInMem`, there's no rolling back.)
+func (me *Ctx) DbTxSub(do func()) (fail any) {
+	if me.DbTx(false); me.Db.Tx == nil {
+		Try(do, func(err any) { fail = err })
+		return
+	}
+	me.Db.savepoints++
+	savepoint := "yo_sp" + str.FromInt(me.Db.savepoints)
+	defer func() { me.Db.savepoints-- }()
+	me.dbTxExec("SAVEPOINT " + savepoint)
+	Try(do, func(err any) { fail = err })
+	if fail != nil {
+		me.dbTxExec("ROLLBACK TO SAVEPOINT " + savepoint)
+	}
+	me.dbTxExec("RELEASE SAVEPOINT " + savepoint)
+	return
+}
+
+func (me *Ctx) dbTxExec(sqlRaw string) {
+	if _, err := me.Db.Tx.ExecContext(me, sqlRaw); err != nil {
+		panic(err)
+	}
+}
+
 func (me *Ctx) DbNoLoggingInDevMode() {
 	if IsDevMode {
 		me.Set(CtxKeyDbNoLogging, true)
@@ -271,11 +336,18 @@ func (me *Ctx) HttpOnPreWriteResponse() {
 	if me.Http.respWriting {
 		panic("new bug: more than one call to HttpOnPreWriteResponse")
 	}
-	me.Http.Resp.Header().Add("Access-Control-Allow-Origin", "*")
 	me.Http.respWriting = true
 	me.httpEnsureCookiesSent()
 }
 
+// HttpRespWriting is true once `HttpOnPreWriteResponse` was called, ie. the response is being written directly (not as API result JSON).
+func (me *Ctx) HttpRespWriting() bool { return me.Http.respWriting }
+
+// HttpIsTls returns whether the request came via HTTPS, either directly or via a TLS-terminating proxy setting `X-Forwarded-Proto`.
+func (me *Ctx) HttpIsTls() bool {
+	return (me.Http.Req.TLS != nil) || (me.Http.Req.Header.Get("X-Forwarded-Proto") == "https")
+}
+
 func (me *Ctx) httpEnsureCookiesSent() {
 	for _, cookie := range me.Http.respCookies {
 		http.SetCookie(me.Http.Resp, cookie)
@@ -309,7 +381,7 @@ func (me *Ctx) HttpSetCookie(cookieName string, cookieValue string, numDays int)
 		MaxAge:   If(cookieValue == "", -1, int(time.Hour*24)*numDays),
 		Path:     "/",
 		SameSite: http.SameSiteStrictMode,
-		Secure:   true,
+		Secure:   (!IsDevMode) || me.HttpIsTls(), // prod always (behind TLS-terminating proxies, the request can be plain HTTP)
 		HttpOnly: true,
 	}
 }
@@ -0,0 +1,137 @@
+package yodb
+
+import (
+	"reflect"
+
+	. "yo/ctx"
+	q "yo/db/query"
+	yojson "yo/json"
+	. "yo/util"
+	"yo/util/kv"
+	"yo/util/sl"
+	"yo/util/str"
+)
+
+type AggFn string
+
+const (
+	AggFnCount AggFn = "COUNT"
+	AggFnSum   AggFn = "SUM"
+	AggFnAvg   AggFn = "AVG"
+	AggFnMin   AggFn = "MIN"
+	AggFnMax   AggFn = "MAX"
+)
+
+// Agg is one aggregate column of an `Aggregate` result row. If `As` is empty, the result key
+// is the lower-cased `Fn` followed by the `Fld` name (eg. "sumAmount"), or just "count" for `AggCountAll`.
+type Agg[TFld q.Field] struct {
+	Fn  AggFn
+	Fld TFld
+	As  string
+}
+
+func AggCountAll[TFld q.Field]() Agg[TFld]      { return Agg[TFld]{Fn: AggFnCount} }
+func AggCount[TFld q.Field](fld TFld) Agg[TFld] { return Agg[TFld]{Fn: AggFnCount, Fld: fld} }
+func AggSum[TFld q.Field](fld TFld) Agg[TFld]   { return Agg[TFld]{Fn: AggFnSum, Fld: fld} }
+func AggAvg[TFld q.Field](fld TFld) Agg[TFld]   { return Agg[TFld]{Fn: AggFnAvg, Fld: fld} }
+func AggMin[TFld q.Field](fld TFld) Agg[TFld]   { return Agg[TFld]{Fn: AggFnMin, Fld: fld} }
+func AggMax[TFld q.Field](fld TFld) Agg[TFld]   { return Agg[TFld]{Fn: AggFnMax, Fld: fld} }
+
+func (me Agg[TFld]) Named(as string) Agg[TFld] {
+	me.As = as
+	return me
+}
+
+func (me *Agg[TFld]) name() string {
+	if me.As != "" {
+		return me.As
+	}
+	return str.Lo(string(me.Fn)) + string(me.Fld)
+}
+
+// Aggregate returns one `kv.Any` per group of `groupBy` values (ascending), each containing the group values
+// (keyed by field name) and all `aggs` results (keyed by `Agg.As`). Without `groupBy`, there's exactly one row.
+func Aggregate[TObj any, TFld q.Field](ctx *Ctx, query q.Query, maxResults int, groupBy []TFld, aggs ...Agg[TFld]) (ret []kv.Any) {
+	inMemUnsupported("Aggregate")
+	desc, args := desc[TObj](), dbArgs{}
+	if len(aggs) == 0 && len(groupBy) == 0 {
+		panic(ErrAggregate_ExpectedAggsOrGroupBy)
+	}
+	names := make([]string, 0, len(groupBy)+len(aggs))
+	stmt := new(sqlStmt)
+	w := (*str.Buf)(stmt).WriteString
+	w("SELECT ")
+	for i, fld := range groupBy {
+		if i > 0 {
+			w(", ")
+		}
+		w(desc.tableName)
+		w(".")
+		w(string(desc.colNameOfField(fld.F())))
+		names = append(names, string(fld))
+	}
+	for i := range aggs {
+		agg := &aggs[i]
+		if name := agg.name(); !str.IsPrtAscii(name) || str.Has(name, `"`) || sl.Has(names, name) {
+			panic(ErrAggregate_ExpectedValidAndUniqueAggNames)
+		} else {
+			names = append(names, name)
+		}
+		if (i > 0) || (len(groupBy) > 0) {
+			w(", ")
+		}
+		switch agg.Fn {
+		case AggFnCount, AggFnSum, AggFnAvg, AggFnMin, AggFnMax:
+		default:
+			panic(ErrAggregate_ExpectedKnownAggFn)
+		}
+		if (agg.Fld == "") && (agg.Fn != AggFnCount) {
+			panic(ErrAggregate_ExpectedFieldForAggFn)
+		}
+		w(string(agg.Fn))
+		w("(")
+		if agg.Fld == "" {
+			w("*")
+		} else {
+			w(desc.tableName)
+			w(".")
+			w(string(desc.colNameOfField(agg.Fld.F())))
+		}
+		w(")")
+		switch {
+		case agg.Fn == AggFnAvg:
+			w("::float8")
+		case agg.Fn == AggFnSum: // PG sums ints into `numeric`s, and float4s into float4s
+			w(If(sl.Has([]reflect.Kind{reflect.Float32, reflect.Float64}, desc.fieldTypeOfField(agg.Fld.F()).Kind()), "::float8", "::int8"))
+		}
+	}
+	stmt.fromAndJoinAndWhereAndOrderBy(desc, false, query, args)
+	if len(groupBy) > 0 {
+		for _, kw := range []string{" GROUP BY ", " ORDER BY "} {
+			w(kw)
+			for i, fld := range groupBy {
+				if i > 0 {
+					w(", ")
+				}
+				w(desc.tableName)
+				w(".")
+				w(string(desc.colNameOfField(fld.F())))
+			}
+		}
+	}
+	stmt.limit(maxResults)
+
+	doStream[kv.Any](ctx, true, stmt, func(rec *kv.Any, _ *bool) {
+		ret = append(ret, *rec)
+	}, args, sl.As(names, func(it string) q.C { return q.C(it) })...)
+	return
+}
+
+// AggregateInto is like `Aggregate` but with the result rows converted into `TRet`s, whose
+// field names must match the `groupBy` field names and the `Agg.As` names.
+func AggregateInto[TObj any, TFld q.Field, TRet any](ctx *Ctx, query q.Query, maxResults int, groupBy []TFld, aggs ...Agg[TFld]) []*TRet {
+	return sl.As(Aggregate[TObj](ctx, query, maxResults, groupBy, aggs...), func(it kv.Any) *TRet {
+		ret := yojson.FromDict[TRet](it)
+		return &ret
+	})
+}
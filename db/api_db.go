@@ -7,24 +7,32 @@ import (
 	q "yo/db/query"
 	. "yo/srv"
 	. "yo/util"
+	"yo/util/kv"
 	"yo/util/sl"
 )
 
 const (
 	errBinOpPrefix = "ExpectedTwoOperandsFor"
 
-	ErrSetQuery    = "Query"
-	ErrSetDbUpdate = "DbUpdate"
-	ErrSetDbDelete = "DbDelete"
+	ErrSetQuery     = "Query"
+	ErrSetDbUpdate  = "DbUpdate"
+	ErrSetDbDelete  = "DbDelete"
+	ErrSetExport    = "Export"
+	ErrSetAggregate = "Aggregate"
 )
 
 func init() {
 	KnownErrSets[ErrSetDbDelete] = []Err{"ExpectedQueryForDelete"}
-	KnownErrSets[ErrSetDbUpdate] = []Err{"ExpectedChangesForUpdate", "ExpectedQueryForUpdate"}
+	KnownErrSets[ErrSetExport] = []Err{"ExpectedCsvOrNdjsonFormat", "ExpectedExportedFields"}
+	KnownErrSets[ErrSetAggregate] = []Err{"ExpectedAggsOrGroupBy", "ExpectedFieldForAggFn", "ExpectedKnownAggFn", "ExpectedValidAndUniqueAggNames"}
+	KnownErrSets[ErrSetDbUpdate] = []Err{"Conflict", "ExpectedChangesForUpdate", "ExpectedNoQueryForVersionedUpdate", "ExpectedQueryForUpdate"}
 	KnownErrSets[ErrSetQuery] = append([]Err{
 		Err("ExpectedOnlyEitherQueryOrQueryFromButNotBoth"),
 		Err("ExpectedSetOperandFor" + opIn),
+		Err("ExpectedStrOperandFor" + opFts),
+		Err("ExpectedFullTextTypeFor" + opFts),
 		Err("ExpectedOneOrNoneButNotMultipleOfFldOrStrOrBoolOrInt"),
+		Err("ExpectedValidPageTok"),
 	}, sl.As([]string{opAnd, opOr, opNot, opIn, opEq, opNe, opGt, opGe, opLt, opLe}, func(it string) Err {
 		return Err(errBinOpPrefix + it)
 	})...)
@@ -46,9 +54,28 @@ func apiMethodPath(typeName string, relMethodPath string) string {
 	return "__/yo/db/" + typeName + "/" + relMethodPath
 }
 
+// apiDeletes are the dev-mode delete APIs per struct, to be told (once all are `Ensure`d) about their `RefOnDelPrevent` referrers
+var apiDeletes = map[*structDesc][]ApiMethod{}
+
+// registerApiErrDepsOnDel has the delete APIs of all structs referred to by `RefOnDelPrevent`s list the referrers' `ErrSetDbRef`s.
+func registerApiErrDepsOnDel() {
+	for _, desc := range ensureDescs {
+		for _, field_name := range desc.refsOnDelPrevent() {
+			for _, api_delete := range apiDeletes[refDesc(desc.fieldTypeOfField(field_name))] {
+				api_delete.CouldFailWith(Err(":" + desc.errSet(ErrSetDbRef)))
+			}
+		}
+	}
+}
+
 func registerApiHandlers[TObj any, TFld q.Field](desc *structDesc) {
 	if IsDevMode {
 		type_name := desc.ty.Name()
+		api_delete_one := api(apiDeleteOne[TObj, TFld]).
+			CouldFailWith(":" + ErrSetDbDelete)
+		api_delete_many := api(apiDeleteMany[TObj, TFld]).
+			CouldFailWith(":"+ErrSetQuery, ":"+ErrSetDbDelete)
+		apiDeletes[desc] = []ApiMethod{api_delete_one, api_delete_many}
 
 		Apis(ApiMethods{
 			apiMethodPath(type_name, "findById"): api(apiFindById[TObj, TFld]),
@@ -56,23 +83,36 @@ func registerApiHandlers[TObj any, TFld q.Field](desc *structDesc) {
 				CouldFailWith(":" + ErrSetQuery),
 			apiMethodPath(type_name, "findMany"): api(apiFindMany[TObj, TFld]).
 				CouldFailWith(":" + ErrSetQuery),
-			apiMethodPath(type_name, "deleteOne"): api(apiDeleteOne[TObj, TFld]).
-				CouldFailWith(":" + ErrSetDbDelete),
-			apiMethodPath(type_name, "deleteMany"): api(apiDeleteMany[TObj, TFld]).
-				CouldFailWith(":"+ErrSetQuery, ":"+ErrSetDbDelete),
+			apiMethodPath(type_name, "findManyPaged"): api(apiFindManyPaged[TObj, TFld]).
+				CouldFailWith(":" + ErrSetQuery),
+			apiMethodPath(type_name, "deleteOne"):  api_delete_one,
+			apiMethodPath(type_name, "deleteMany"): api_delete_many,
 			apiMethodPath(type_name, "updateOne"): api(apiUpdateOne[TObj, TFld]).
-				CouldFailWith(":"+ErrSetDbUpdate, yoctx.ErrDbUpdExpectedIdGt0),
+				CouldFailWith(":"+ErrSetDbUpdate, yoctx.ErrDbUpdExpectedIdGt0).CouldFailWith(desc.errDepsOnWrite()...),
 			apiMethodPath(type_name, "updateMany"): api(apiUpdateMany[TObj, TFld]).
-				CouldFailWith(":"+ErrSetQuery, ":"+ErrSetDbUpdate),
+				CouldFailWith(":"+ErrSetQuery, ":"+ErrSetDbUpdate).CouldFailWith(desc.errDepsOnWrite()...),
+			apiMethodPath(type_name, "export"): api(apiExport[TObj, TFld]).
+				CouldFailWith(":"+ErrSetQuery, ":"+ErrSetExport),
 			apiMethodPath(type_name, "count"): api(apiCount[TObj, TFld]).
 				CouldFailWith(":" + ErrSetQuery),
-			apiMethodPath(type_name, "createOne"):  api(apiCreateOne[TObj, TFld]),
-			apiMethodPath(type_name, "createMany"): api(apiCreateMany[TObj, TFld]),
+			apiMethodPath(type_name, "aggregate"): api(apiAggregate[TObj, TFld]).
+				CouldFailWith(":"+ErrSetQuery, ":"+ErrSetAggregate),
+			apiMethodPath(type_name, "createOne"):  api(apiCreateOne[TObj, TFld]).CouldFailWith(desc.errDepsOnWrite()...),
+			apiMethodPath(type_name, "createMany"): api(apiCreateMany[TObj, TFld]).CouldFailWith(desc.errDepsOnWrite()...),
 		})
+		if desc.constraints.audited != nil {
+			Apis(ApiMethods{
+				apiMethodPath(type_name, "history"): api(apiHistory[TObj, TFld]),
+			})
+		}
 	}
 }
 
 type retCount struct{ Count int64 }
+type retPage[TObj any] struct {
+	Result      []*TObj
+	NextPageTok string
+}
 type argQuery[TObj any, TFld q.Field] struct {
 	Query     *ApiQueryExpr[TObj, TFld]
 	QueryFrom *TObj
@@ -95,6 +135,13 @@ func (me *argQuery[TObj, TFld]) toDbQ() q.Query {
 }
 func (me *argQuery[TObj, TFld]) toDbO() []q.OrderBy {
 	return sl.As(me.OrderBy, func(it *ApiOrderBy[TObj, TFld]) q.OrderBy {
+		if it.FTS != nil {
+			if len(desc[TObj]().constraints.fullText) == 0 {
+				panic(ErrQuery_ExpectedFullTextTypeForFTS)
+			}
+			rank := FullTextRank(*it.FTS)
+			return If(it.Desc, rank.Desc(), rank.Asc())
+		}
 		fld := q.F(it.Fld)
 		return If(it.Desc, fld.Desc(), fld.Asc())
 	})
@@ -114,10 +161,33 @@ func apiFindMany[TObj any, TFld q.Field](this *ApiCtx[argQuery[TObj, TFld], Retu
 	this.Ret.Result = FindMany[TObj](this.Ctx, this.Args.toDbQ(), int(this.Args.Max), nil, this.Args.toDbO()...)
 }
 
+func apiFindManyPaged[TObj any, TFld q.Field](this *ApiCtx[struct {
+	argQuery[TObj, TFld]
+	PageTok string
+}, retPage[TObj]]) {
+	this.Ret.Result, this.Ret.NextPageTok = Paged[TObj](this.Ctx, this.Args.toDbQ(), If(this.Args.Max > 0, int(this.Args.Max), 100), this.Args.PageTok, this.Args.toDbO()...)
+}
+
+func apiExport[TObj any, TFld q.Field](this *ApiCtx[struct {
+	argQuery[TObj, TFld]
+	Format ExportFormat // if empty, `ExportFormatCsv`
+	Fields []TFld       // if empty, all exported fields
+}, None]) {
+	ExportToHttp[TObj](this.Ctx, this.Args.Format, this.Args.toDbQ(), int(this.Args.Max), this.Args.toDbO(), sl.As(this.Args.Fields, TFld.F)...)
+}
+
 func apiCount[TObj any, TFld q.Field](this *ApiCtx[argQuery[TObj, TFld], retCount]) {
 	this.Ret.Count = Count[TObj](this.Ctx, this.Args.toDbQ(), "", nil)
 }
 
+func apiAggregate[TObj any, TFld q.Field](this *ApiCtx[struct {
+	argQuery[TObj, TFld]
+	GroupBy []TFld
+	Aggs    []Agg[TFld]
+}, Return[[]kv.Any]]) {
+	this.Ret.Result = Aggregate[TObj](this.Ctx, this.Args.toDbQ(), int(this.Args.Max), this.Args.GroupBy, this.Args.Aggs...)
+}
+
 func apiCreateOne[TObj any, TFld q.Field](this *ApiCtx[TObj, ObjRef[TObj]]) {
 	id := CreateOne[TObj](this.Ctx, this.Args)
 	this.Ret.Id = id
@@ -137,6 +207,13 @@ func apiDeleteMany[TObj any, TFld q.Field](this *ApiCtx[argQuery[TObj, TFld], re
 	this.Ret.Count = Delete[TObj](this.Ctx, this.Args.toDbQ())
 }
 
+func apiHistory[TObj any, TFld q.Field](this *ApiCtx[struct {
+	Id  I64 // if 0, all objects' history
+	Max uint32
+}, Return[[]*AuditEntry]]) {
+	this.Ret.Result = History[TObj](this.Ctx, this.Args.Id, int(this.Args.Max))
+}
+
 type ApiUpdateArgs[TObj any, TFld q.Field] struct {
 	Id            I64
 	Changes       TObj
@@ -147,7 +224,12 @@ func apiUpdateOne[TObj any, TFld q.Field](this *ApiCtx[ApiUpdateArgs[TObj, TFld]
 	if this.Args.Id <= 0 {
 		panic(Err(yoctx.ErrDbUpdExpectedIdGt0))
 	}
-	this.Ret.Count = Update[TObj](this.Ctx, &this.Args.Changes, ColID.Equal(this.Args.Id), (len(this.Args.ChangedFields) == 0), sl.As(this.Args.ChangedFields, TFld.F)...)
+	var where q.Query = ColID.Equal(this.Args.Id)
+	if desc := desc[TObj](); desc.constraints.versioned != "" { // `Update` takes `Versioned` objects only by their own id
+		where = nil
+		reflFieldSetInt(&this.Args.Changes, desc.fields[0], int64(this.Args.Id))
+	}
+	this.Ret.Count = Update[TObj](this.Ctx, &this.Args.Changes, where, (len(this.Args.ChangedFields) == 0), sl.As(this.Args.ChangedFields, TFld.F)...)
 }
 
 func apiUpdateMany[TObj any, TFld q.Field](this *ApiCtx[struct {
@@ -161,6 +243,7 @@ func apiUpdateMany[TObj any, TFld q.Field](this *ApiCtx[struct {
 type ApiOrderBy[TObj any, TFld q.Field] struct {
 	Fld  TFld
 	Desc bool
+	FTS  *string // only for `FullText` types: if set, orders by match rank against these search terms instead of by `Fld`
 }
 type ApiQueryVal[TObj any, TFld q.Field] struct {
 	Fld  *TFld
@@ -179,6 +262,7 @@ type ApiQueryExpr[TObj any, TFld q.Field] struct {
 	GT  []ApiQueryVal[TObj, TFld]
 	GE  []ApiQueryVal[TObj, TFld]
 	IN  []ApiQueryVal[TObj, TFld]
+	FTS []ApiQueryVal[TObj, TFld] // only for `FullText` types: exactly one `Str` operand, the search terms
 }
 
 const ( // must be in sync with the `ApiQueryExpr` field names
@@ -192,6 +276,7 @@ const ( // must be in sync with the `ApiQueryExpr` field names
 	opLe  = "LE"
 	opGt  = "GT"
 	opGe  = "GE"
+	opFts = "FTS"
 )
 
 func (me *ApiQueryExpr[TObj, TFld]) Validate() {
@@ -227,6 +312,14 @@ func (me *ApiQueryExpr[TObj, TFld]) Validate() {
 		num_found++
 		me.NOT.Validate()
 	}
+	if len(me.FTS) > 0 {
+		num_found++
+		if (len(me.FTS) != 1) || (me.FTS[0].Str == nil) {
+			panic(ErrQuery_ExpectedStrOperandForFTS)
+		} else if len(desc[TObj]().constraints.fullText) == 0 {
+			panic(ErrQuery_ExpectedFullTextTypeForFTS)
+		}
+	}
 }
 
 func (me *ApiQueryExpr[TObj, TFld]) toDbQ() q.Query {
@@ -239,6 +332,8 @@ func (me *ApiQueryExpr[TObj, TFld]) toDbQ() q.Query {
 		return q.Not(me.NOT.toDbQ())
 	case len(me.IN) >= 2:
 		return q.In(me.IN[0].val(), sl.As(me.IN[1:], func(it ApiQueryVal[TObj, TFld]) any { return it.val() })...)
+	case len(me.FTS) == 1:
+		return FullTextMatch(*me.FTS[0].Str)
 	}
 	for bin_op, q_f := range map[*[]ApiQueryVal[TObj, TFld]]func(any, any) q.Query{&me.EQ: q.Equal, &me.NE: q.NotEqual, &me.LT: q.LessThan, &me.LE: q.LessOrEqual, &me.GT: q.GreaterThan, &me.GE: q.GreaterOrEqual} {
 		if bin_op := *bin_op; len(bin_op) == 2 {
@@ -0,0 +1,185 @@
+package yodb
+
+import (
+	"time"
+
+	. "yo/ctx"
+	q "yo/db/query"
+	. "yo/util"
+	"yo/util/sl"
+	"yo/util/str"
+)
+
+// Audited makes every insert, update and delete on the `Ensure`d table also write a history row
+// (see `AuditEntry`) into a `<table>_history_` table via a DB trigger.
+type Audited struct{}
+
+func (Audited) qFs() []q.F { return nil }
+
+// AuditAccountId, if set (as done by `yoauth`), provides the acting user account id for `Audited` writes.
+var AuditAccountId func(*Ctx) I64
+
+type AuditEntry struct {
+	Id         I64
+	DtMade     *DateTime
+	ObjId      I64
+	Op         Text
+	DiffBefore JsonMap[any]
+	DiffAfter  JsonMap[any]
+	AccountId  I64
+	JobRunId   I64
+	JobTaskId  I64
+}
+
+func (me *structDesc) auditTableName() string { return auditTableNameOf(me.tableName) }
+
+func auditTableNameOf(tableName string) string { return tableName + "_history_" }
+func auditTableIdxNameOf(auditTableName string) string {
+	return "idx_t_" + auditTableName + "_c_obj_id_"
+}
+
+// auditTriggerArgs are the audit table name followed by the columns to leave out of the diffs.
+func (me *structDesc) auditTriggerArgs() []string {
+	ret := []string{me.auditTableName()}
+	if len(me.constraints.fullText) > 0 {
+		ret = append(ret, string(ColFullText))
+	}
+	return ret
+}
+
+func schemaAuditStmts(desc *structDesc) (ret []*sqlStmt) {
+	for _, sql_raw := range []string{
+		str.Repl(`CREATE TABLE IF NOT EXISTS {table_name} (
+			id_ bigserial PRIMARY KEY,
+			dt_made_ timestamp without time zone NOT NULL DEFAULT (current_timestamp),
+			obj_id_ int8 NOT NULL DEFAULT (0),
+			op_ text NOT NULL DEFAULT (''),
+			diff_before_ jsonb NULL DEFAULT (NULL),
+			diff_after_ jsonb NULL DEFAULT (NULL),
+			account_id_ int8 NOT NULL DEFAULT (0),
+			job_run_id_ int8 NOT NULL DEFAULT (0),
+			job_task_id_ int8 NOT NULL DEFAULT (0)
+		)`, str.Dict{"table_name": desc.auditTableName()}),
+		"CREATE INDEX IF NOT EXISTS " + auditTableIdxNameOf(desc.auditTableName()) + " ON " + desc.auditTableName() + " (obj_id_)",
+		`CREATE OR REPLACE FUNCTION on_yo_db_obj_audit()
+			RETURNS TRIGGER
+			LANGUAGE plpgsql AS
+			$func$
+			DECLARE
+				skip_cols text[] := TG_ARGV[1:TG_NARGS-1];
+				old_json jsonb := CASE WHEN TG_OP = 'INSERT' THEN NULL ELSE (to_jsonb(OLD) - skip_cols) END;
+				new_json jsonb := CASE WHEN TG_OP = 'DELETE' THEN NULL ELSE (to_jsonb(NEW) - skip_cols) END;
+			BEGIN
+				IF TG_OP = 'UPDATE' THEN
+					SELECT jsonb_object_agg(key, value) INTO old_json FROM jsonb_each(to_jsonb(OLD) - skip_cols) WHERE (new_json -> key) IS DISTINCT FROM value;
+					SELECT jsonb_object_agg(key, value) INTO new_json FROM jsonb_each(to_jsonb(NEW) - skip_cols) WHERE ((to_jsonb(OLD) - skip_cols) -> key) IS DISTINCT FROM value;
+					IF old_json IS NULL AND new_json IS NULL THEN
+						RETURN NULL;
+					END IF;
+				END IF;
+				EXECUTE format('INSERT INTO %I (obj_id_, op_, diff_before_, diff_after_, account_id_, job_run_id_, job_task_id_) VALUES ($1, $2, $3, $4, $5, $6, $7)', TG_ARGV[0])
+					USING COALESCE(NEW.id_, OLD.id_), TG_OP, old_json, new_json,
+						COALESCE(NULLIF(current_setting('yo.audit_account_id', true), '')::int8, 0),
+						COALESCE(NULLIF(current_setting('yo.audit_job_run_id', true), '')::int8, 0),
+						COALESCE(NULLIF(current_setting('yo.audit_job_task_id', true), '')::int8, 0);
+				RETURN NULL;
+			END
+			$func$`,
+		"CREATE OR REPLACE TRIGGER " + desc.tableName + "onAudit AFTER INSERT OR UPDATE OR DELETE ON " + desc.tableName + " FOR EACH ROW EXECUTE FUNCTION on_yo_db_obj_audit(" +
+			str.Join(sl.As(desc.auditTriggerArgs(), func(it string) string { return "'" + it + "'" }), ", ") + ")",
+	} {
+		stmt := new(sqlStmt)
+		(*str.Buf)(stmt).WriteString(sql_raw)
+		ret = append(ret, stmt)
+	}
+	return
+}
+
+// schemaHasAuditTrigger returns whether the `Audited` trigger exists with the current `auditTriggerArgs`.
+func schemaHasAuditTrigger(ctx *Ctx, desc *structDesc) bool {
+	stmt := new(sqlStmt)
+	(*str.Buf)(stmt).WriteString("SELECT COUNT(*) FROM pg_trigger WHERE tgname = @N AND tgargs = @A")
+	tg_args := []byte(str.Join(desc.auditTriggerArgs(), "\x00") + "\x00")
+	return *doSelect[int64](ctx, true, stmt, dbArgs{"N": str.Lo(desc.tableName + "onAudit"), "A": tg_args}, 1)[0] > 0
+}
+
+// schemaAuditRenameStmts are for tables renamed via `Ensure`'s `oldTableName`, whose old-named trigger would otherwise
+// keep writing into the old-named history table alongside the new ones. The history moves over to the new name.
+func schemaAuditRenameStmts(desc *structDesc) (ret []*sqlStmt) {
+	old_audit_table_name := auditTableNameOf(desc.mig.oldTableName)
+	for _, sql_raw := range []string{
+		"DROP TRIGGER IF EXISTS " + desc.mig.oldTableName + "onAudit ON " + desc.tableName,
+		"ALTER TABLE IF EXISTS " + old_audit_table_name + " RENAME TO " + desc.auditTableName(),
+		"ALTER INDEX IF EXISTS " + auditTableIdxNameOf(old_audit_table_name) + " RENAME TO " + auditTableIdxNameOf(desc.auditTableName()),
+	} {
+		stmt := new(sqlStmt)
+		(*str.Buf)(stmt).WriteString(sql_raw)
+		ret = append(ret, stmt)
+	}
+	return
+}
+
+func schemaAuditDropTrigger(desc *structDesc) *sqlStmt {
+	stmt := new(sqlStmt)
+	(*str.Buf)(stmt).WriteString("DROP TRIGGER IF EXISTS " + desc.tableName + "onAudit ON " + desc.tableName)
+	return stmt
+}
+
+// audited runs `do` (a write on `desc`'s table) such that the `Audited` trigger can pick up the acting account and job ids:
+// these are set transaction-locally, so if `ctx` has no TX yet, a short-lived one just for `do` is used.
+func audited(ctx *Ctx, desc *structDesc, do func()) {
+	if desc.constraints.audited == nil {
+		do()
+		return
+	}
+	if ctx.Db.Tx == nil {
+		var err error
+		time_started := time.Now()
+		ctx.Db.Tx, err = DB.BeginTx(ctx, nil)
+		dbStatsRecord(ctx, "BEGIN", nil, time_started, 0, 0, false)
+		if err != nil {
+			panic(err)
+		}
+		defer func() {
+			tx, time_started := ctx.Db.Tx, time.Now()
+			ctx.Db.Tx = nil
+			if fail := recover(); fail != nil {
+				_ = tx.Rollback()
+				dbStatsRecord(ctx, "ROLLBACK", nil, time_started, 0, 0, false)
+				panic(fail)
+			}
+			err := tx.Commit()
+			dbStatsRecord(ctx, "COMMIT", nil, time_started, 0, 0, false)
+			if err != nil {
+				panic(err)
+			}
+		}()
+	}
+
+	var account_id, job_run_id, job_task_id I64
+	if AuditAccountId != nil {
+		account_id = AuditAccountId(ctx)
+	}
+	if ctx.Job != nil {
+		job_run_id, job_task_id = I64(ctx.Job.RunId), I64(ctx.Job.TaskId)
+	}
+	stmt := new(sqlStmt)
+	(*str.Buf)(stmt).WriteString("SELECT set_config('yo.audit_account_id', @A, true), set_config('yo.audit_job_run_id', @R, true), set_config('yo.audit_job_task_id', @T, true)")
+	_ = doExec(ctx, stmt, dbArgs{"A": str.FromI64(int64(account_id), 10), "R": str.FromI64(int64(job_run_id), 10), "T": str.FromI64(int64(job_task_id), 10)})
+	do()
+}
+
+// History returns the `AuditEntry`s (newest first) of the `Audited` table for `T`, optionally only for the given object.
+func History[T any](ctx *Ctx, objId I64, maxResults int) []*AuditEntry {
+	inMemUnsupported("History")
+	desc_obj := desc[T]()
+	if desc_obj.constraints.audited == nil {
+		panic("History on non-Audited " + desc_obj.tableName)
+	}
+	desc, args := *desc[AuditEntry](), dbArgs{}
+	desc.tableName = desc_obj.auditTableName()
+	stmt := new(sqlStmt).selCols(&desc, nil, true).
+		fromAndJoinAndWhereAndOrderBy(&desc, false, If[q.Query](objId <= 0, nil, q.C("obj_id_").Equal(objId)), args, ColID.Desc()).
+		limit(maxResults)
+	return doSelect[AuditEntry](ctx, true, stmt, args, maxResults)
+}
@@ -0,0 +1,47 @@
+package yodb
+
+import (
+	"testing"
+
+	q "yo/db/query"
+	"yo/util/str"
+)
+
+func TestAuditStmts(t *testing.T) {
+	desc := *desc[testInMemParent]()
+	desc.constraints.audited = &Audited{}
+	for _, test := range []struct {
+		fullText []q.F
+		expect   string
+	}{
+		{nil, "on_yo_db_obj_audit('test_in_mem_parent__history_')"},
+		{[]q.F{"Name"}, "on_yo_db_obj_audit('test_in_mem_parent__history_', 'fts_')"},
+	} {
+		desc.constraints.fullText = test.fullText
+		stmts := schemaAuditStmts(&desc)
+		if sql := stmts[0].String(); !str.Has(sql, "CREATE TABLE IF NOT EXISTS test_in_mem_parent__history_ ") {
+			t.Errorf("expected the audit table name in: %s", sql)
+		}
+		if sql := stmts[len(stmts)-1].String(); !str.Ends(sql, test.expect) {
+			t.Errorf("expected %s in: %s", test.expect, sql)
+		}
+		if sql := stmts[len(stmts)-2].String(); str.Has(sql, "'fts_'") || str.Has(sql, "'history_'") {
+			t.Errorf("expected no hard-coded names in: %s", sql)
+		}
+	}
+}
+
+func TestAuditRenameStmts(t *testing.T) {
+	desc := *desc[testInMemParent]()
+	desc.mig.oldTableName = "test_in_mem_old"
+	stmts := schemaAuditRenameStmts(&desc)
+	for i, expect := range []string{
+		"DROP TRIGGER IF EXISTS test_in_mem_oldonAudit ON test_in_mem_parent_",
+		"ALTER TABLE IF EXISTS test_in_mem_old_history_ RENAME TO test_in_mem_parent__history_",
+		"ALTER INDEX IF EXISTS idx_t_test_in_mem_old_history__c_obj_id_ RENAME TO idx_t_test_in_mem_parent__history__c_obj_id_",
+	} {
+		if sql := stmts[i].String(); sql != expect {
+			t.Errorf("expected %s, got: %s", expect, sql)
+		}
+	}
+}
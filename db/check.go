@@ -0,0 +1,132 @@
+package yodb
+
+import (
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"strconv"
+	"time"
+
+	q "yo/db/query"
+	. "yo/util"
+	"yo/util/sl"
+	"yo/util/str"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Check makes the DB enforce that `Query` holds for every row of the `Ensure`d table, via a Postgres CHECK
+// constraint named from `Name`. Violating writes panic with `Err` (defaulting to `DbCheck_<TypeName>_<Name>`).
+// Like indices, checks are only (re)created on table creation, alterations or `Ensure` with `constraintsChanged`.
+// Any `q.V` operands are inlined as literals, so should be of simple (bool, number, string, `*DateTime`, `Bytes`) types.
+type Check[T q.Field] struct {
+	Name  string
+	Query q.Query
+	Err   Err
+}
+
+func (Check[TFld]) qFs() []q.F { return nil }
+
+type checkConstraint struct {
+	name string // the Postgres constraint name
+	sql  string
+	err  Err
+	cond q.Query
+}
+
+// checkErrs maps the Postgres constraint names of all `Check`s to their `Err`s
+var checkErrs = map[string]Err{}
+
+func (me Check[TFld]) constraint(desc *structDesc) *checkConstraint {
+	if (me.Query == nil) || (me.Name == "") || (ToIdent(me.Name) != me.Name) {
+		panic(desc.tableName + ": `Check` needs a `Query` and an identifier-like `Name`, got '" + me.Name + "'")
+	}
+	ret := &checkConstraint{name: str.Lo("ck_" + desc.tableName + "_" + me.Name), err: me.Err, cond: me.Query}
+	if len(ret.name) > 63 {
+		panic(desc.tableName + ": `Check` name '" + me.Name + "' too long for Postgres")
+	} else if ret.err == "" {
+		ret.err = Err("DbCheck_" + desc.ty.Name() + "_" + me.Name)
+	}
+	var buf str.Buf
+	args := pgx.NamedArgs{}
+	me.Query.Sql(&buf, func(fieldName q.F) q.C {
+		if !sl.Has(desc.fields, fieldName) {
+			panic(desc.tableName + ": `Check` '" + me.Name + "' refers to unknown field '" + string(fieldName) + "'")
+		}
+		return desc.colNameOfField(fieldName)
+	}, args, false)
+	args = dbArgsCleanUpForPgx(args)
+	literals := make(str.Dict, len(args))
+	for arg_name, arg_val := range args {
+		literals["@"+arg_name+" "] = sqlLiteral(arg_val) + " "
+	}
+	ret.sql = str.Replace(buf.String(), literals)
+	return ret
+}
+
+func sqlLiteral(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "NULL"
+	case time.Time:
+		return "'" + v.Format("2006-01-02 15:04:05.999999") + "'::timestamp"
+	case []byte:
+		return "'\\x" + hex.EncodeToString(v) + "'::bytea"
+	}
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Bool:
+		return If(rv.Bool(), "true", "false")
+	case reflect.String:
+		return "'" + str.Replace(rv.String(), str.Dict{"'": "''"}) + "'"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return str.FromI64(rv.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'g', -1, 64)
+	}
+	panic(str.Fmt("`Check` operand %T not supported: %#v", v, v))
+}
+
+// schemaReCreateChecks drops all `Check`-made constraints of `desc`'s table, then adds its current ones.
+func schemaReCreateChecks(desc *structDesc) (ret []*sqlStmt) {
+	stmt_drop_checks := new(sqlStmt)
+	(*str.Buf)(stmt_drop_checks).WriteString(str.Repl(`DO $do$
+		DECLARE con_name text;
+		BEGIN
+			FOR con_name IN SELECT conname FROM pg_constraint WHERE (conrelid = '{table_name}'::regclass) AND (contype = 'c') AND (conname LIKE 'ck\_{table_name}\_%') LOOP
+				EXECUTE 'ALTER TABLE {table_name} DROP CONSTRAINT ' || quote_ident(con_name);
+			END LOOP;
+		END
+		$do$`, str.Dict{"table_name": str.Lo(desc.tableName)}))
+	ret = append(ret, stmt_drop_checks)
+	for _, check := range desc.constraints.checks {
+		stmt_add_check := new(sqlStmt)
+		(*str.Buf)(stmt_add_check).WriteString("ALTER TABLE " + desc.tableName + " ADD CONSTRAINT " + check.name + " CHECK " + check.sql)
+		ret = append(ret, stmt_add_check)
+	}
+	return
+}
+
+// dbErrFrom returns the `Err` of the `Check` (or other constraint, see `constraintErrs`) violated, if that's what `err` is, else `err`.
+func dbErrFrom(err error) any {
+	var pg_err *pgconn.PgError
+	if errors.As(err, &pg_err) {
+		switch pg_err.Code {
+		case "23514": // check_violation
+			if err_check := checkErrs[pg_err.ConstraintName]; err_check != "" {
+				return err_check
+			}
+		case "23505", "23503": // unique_violation, foreign_key_violation
+			if err_constraint := constraintErrs[pg_err.ConstraintName]; err_constraint != "" {
+				return err_constraint
+			}
+		case "23502": // not_null_violation
+			if err_not_null := constraintErrs[pg_err.TableName+"."+pg_err.ColumnName]; err_not_null != "" {
+				return err_not_null
+			}
+		}
+	}
+	return err
+}
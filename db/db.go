@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"reflect"
+	"sync/atomic"
 	"time"
 
 	. "yo/cfg"
@@ -21,17 +22,35 @@ import (
 var (
 	inited                = false
 	codegenDBStuff func() = nil
+	dbReadOnly     []*sql.DB
+	dbReadOnlyNext atomic.Uint32
 )
 
 func InitAndConnectAndMigrateAndMaybeCodegen() (dbStructs []reflect.Type) {
 	if inited {
 		panic("db.Init called twice?")
 	}
+	pageTokSignKeyEnsure()
 	if codegenDBStuff != nil {
 		codegenDBStuff()
 	}
 
-	conn_cfg, err := pgx.ParseConfig(Cfg.YO_DB_CONN_URL)
+	DB = dbOpen(Cfg.YO_DB_CONN_URL)
+	for _, conn_url := range Cfg.YO_DB_CONN_URL_READONLY {
+		dbReadOnly = append(dbReadOnly, dbOpen(conn_url))
+	}
+	doEnsureDbStructTables()
+	registerApiErrDepsOnDel()
+	for _, desc := range ensureDescs {
+		dbStructs = append(dbStructs, desc.ty)
+	}
+	inited = true
+	notifyListenIfSubs()
+	return
+}
+
+func dbOpen(connUrl string) (ret *sql.DB) {
+	conn_cfg, err := pgx.ParseConfig(connUrl)
 	if err != nil {
 		panic(err)
 	}
@@ -41,17 +60,21 @@ func InitAndConnectAndMigrateAndMaybeCodegen() (dbStructs []reflect.Type) {
 		Logger:   dbLogger{},
 	}
 	str_conn := stdlib.RegisterConnConfig(conn_cfg)
-	for DB, err = sql.Open("pgx", str_conn); err != nil; time.Sleep(time.Second) {
+	for ret, err = sql.Open("pgx", str_conn); err != nil; time.Sleep(time.Second) {
 		yolog.Println("DB connect: " + err.Error())
 	}
-	doEnsureDbStructTables()
-	for _, desc := range ensureDescs {
-		dbStructs = append(dbStructs, desc.ty)
-	}
-	inited = true
 	return
 }
 
+// dbForReads is the primary `DB` for all writes, TX-ed work, schema work during `Init` and `Ctx.Db.ReadYourWrites`,
+// else (if any are configured) the next `YO_DB_CONN_URL_READONLY` replica in round-robin order.
+func dbForReads(ctx *Ctx) *sql.DB {
+	if (len(dbReadOnly) == 0) || (!inited) || ctx.Db.ReadYourWrites {
+		return DB
+	}
+	return dbReadOnly[int(dbReadOnlyNext.Add(1))%len(dbReadOnly)]
+}
+
 type dbLogger struct{}
 
 func (dbLogger) Log(ctx context.Context, level tracelog.LogLevel, msg string, data kv.Any) {
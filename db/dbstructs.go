@@ -2,6 +2,7 @@ package yodb
 
 import (
 	"bytes"
+	"os"
 	"reflect"
 	"time"
 	"unsafe"
@@ -20,6 +21,7 @@ const (
 	ColID         = q.C("id_")
 	ColCreatedAt  = q.C("dt_made_")
 	ColModifiedAt = q.C("dt_mod_")
+	ColDeletedAt  = q.C("dt_del_") // only for `SoftDelete` tables, and not a struct field
 	numStdCols    = 3
 
 	FieldID         = q.F("Id")
@@ -80,6 +82,21 @@ func (me *Ref[T, _]) structDesc() (ret *structDesc) {
 	}
 	return
 }
+func (*Ref[T, OnDel]) preload(ctx *yoctx.Ctx, refs []any) {
+	ids := sl.WithoutDupls(sl.Where(sl.As(refs, func(it any) I64 { return it.(*Ref[T, OnDel]).id }), func(it I64) bool { return it != 0 }))
+	if len(ids) == 0 {
+		return
+	}
+	by_id := map[I64]*T{}
+	for _, obj := range FindMany[T](ctx, ColID.In(ids.ToAnys()...), 0, nil) {
+		by_id[reflFieldValueOf(obj, FieldID).(I64)] = obj
+	}
+	for _, it := range refs {
+		ref := it.(*Ref[T, OnDel])
+		ref.self = by_id[ref.id]
+	}
+}
+func (me *Ref[T, _]) setSelf(obj any)              { me.self = obj.(*T) }
 func (me *Ref[_, _]) MarshalJSON() ([]byte, error) { return []byte(str.FromI64(int64(me.id), 10)), nil }
 func (me *Ref[_, _]) UnmarshalJSON(json []byte) error {
 	me.self, me.id = nil, 0
@@ -110,13 +127,18 @@ var (
 	_ dbJsonValue = &JsonArr[any]{}
 )
 
-func (me *IsJsonOf[T]) init(selfPtr any)                               { me.self = selfPtr.(*T) }
-func (me *IsJsonOf[T]) scan(jsonb []byte) error                        { return yojson.Unmarshal(jsonb, me.self) }
-func (me *IsJsonOf[T]) get() any                                       { return me.self }
-func (*IsJsonOf[T]) getOther(ptr unsafe.Pointer) any                   { panic("TODO") }
-func (me *IsJsonOf[T]) initOther(dst unsafe.Pointer) (ret dbJsonValue) { panic("TODO") }
-func (me *JsonMap[T]) init(any)                                        { *me = JsonMap[T]{} }
-func (me *JsonMap[T]) scan(jsonb []byte) error                         { return yojson.Unmarshal(jsonb, me) }
+func (me *IsJsonOf[T]) init(selfPtr any)             { me.self = selfPtr.(*T) }
+func (me *IsJsonOf[T]) scan(jsonb []byte) error      { return yojson.Unmarshal(jsonb, me.self) }
+func (me *IsJsonOf[T]) get() any                     { return me.self }
+func (*IsJsonOf[T]) getOther(ptr unsafe.Pointer) any { return *((*T)(ptr)) }
+func (me *IsJsonOf[T]) initOther(dst unsafe.Pointer) (ret dbJsonValue) {
+	self := (*T)(dst)
+	ret = any(self).(dbJsonValue)
+	ret.init(self)
+	return
+}
+func (me *JsonMap[T]) init(any)                { *me = JsonMap[T]{} }
+func (me *JsonMap[T]) scan(jsonb []byte) error { return yojson.Unmarshal(jsonb, me) }
 func (me *JsonMap[T]) get() any {
 	if (me == nil) || (*me == nil) {
 		return JsonMap[T]{}
@@ -176,12 +198,23 @@ type Index[T q.Field] []T
 type ReadOnly[T q.Field] []T
 type NoUpdTrigger[T q.Field] []T
 type AlwaysFetch[T q.Field] []T
+type Versioned[T q.Field] []T // exactly one integer field, for optimistic locking in `Update`s, which then must be single-object ones (`where == nil`)
+
+// SoftDelete makes `Delete` set `ColDeletedAt` instead of removing rows, and all other reads and writes skip such
+// rows unless their query is a `WithDeleted` one. Note that soft-deleted rows still count for `Unique`s (so `Upsert`s and `UpsertMany`s
+// conflicting with them restore them), and that soft-deleting applies no `Ref` on-delete semantics: referencing rows stay untouched
+// (and `RefOnDelPrevent` doesn't prevent) until the purge.
+type SoftDelete struct {
+	PurgeAfterDays int // if > 0, soft-deleted rows are hard-deleted after this many days by `yojobs.SoftDelPurgeJobDef`
+}
 
 func (me Unique[TFld]) qFs() []q.F       { return sl.As(me, func(it TFld) q.F { return it.F() }) }
 func (me Index[TFld]) qFs() []q.F        { return sl.As(me, func(it TFld) q.F { return it.F() }) }
 func (me ReadOnly[TFld]) qFs() []q.F     { return sl.As(me, func(it TFld) q.F { return it.F() }) }
 func (me NoUpdTrigger[TFld]) qFs() []q.F { return sl.As(me, func(it TFld) q.F { return it.F() }) }
 func (me AlwaysFetch[TFld]) qFs() []q.F  { return sl.As(me, func(it TFld) q.F { return it.F() }) }
+func (me Versioned[TFld]) qFs() []q.F    { return sl.As(me, func(it TFld) q.F { return it.F() }) }
+func (SoftDelete) qFs() []q.F            { return nil }
 
 type structDesc struct {
 	ty          reflect.Type
@@ -194,11 +227,20 @@ type structDesc struct {
 		readOnly     []q.F
 		noUpdTrigger []q.F
 		alwaysFetch  []q.F
+		versioned    q.F
+		softDelete   *SoftDelete
+		audited      *Audited
+		notify       *Notify
+		fullText     []q.F
+		checks       []*checkConstraint
 	}
 	mig struct {
 		oldTableName            string
 		renamesOldColToNewField map[q.C]q.F
 		constraintsChanged      bool
+		steps                   Migrations
+		ackDestructive          []q.C
+		colConvs                ColConvs
 	}
 }
 
@@ -248,6 +290,12 @@ func isWhatDbJsonType(ty reflect.Type) (isDbJsonDictType bool, isDbJsonArrType b
 }
 
 func isDbRefType(ty reflect.Type) bool { return (dbRefType(ty) != "") }
+
+// refDesc returns the `structDesc` of the type referenced by `refType`, a `Ref[T, OnDel]`.
+func refDesc(refType reflect.Type) *structDesc {
+	return reflect.New(refType).Interface().(interface{ structDesc() *structDesc }).structDesc()
+}
+
 func dbRefType(ty reflect.Type) string {
 	type_name := ty.Name()
 	if idx := str.IdxSub(type_name, "Ref["); (idx == 0) && (ty.PkgPath() == yodbPkg.PkgPath()) && str.Ends(type_name, "]") {
@@ -310,6 +358,16 @@ func reflFieldValueOf[T any](it *T, fieldName q.F) any {
 	return reflFieldValue(reflect.ValueOf(it).Elem().FieldByName(string(fieldName)), field.Type)
 }
 
+func reflFieldSetInt[T any](it *T, fieldName q.F, value int64) {
+	rv := reflect.ValueOf(it).Elem().FieldByName(string(fieldName))
+	rv = reflect.NewAt(rv.Type(), unsafe.Pointer(rv.UnsafeAddr())).Elem()
+	if rv.CanInt() {
+		rv.SetInt(value)
+	} else {
+		rv.SetUint(uint64(value))
+	}
+}
+
 func reflFieldValue(rvField reflect.Value, fieldType reflect.Type) any {
 	if !rvField.IsValid() {
 		return nil
@@ -463,11 +521,46 @@ func Ensure[TObj any, TFld q.Field](oldTableName string, renamesOldColToNewField
 			desc.constraints.readOnly = sl.With(desc.constraints.readOnly, constraints.qFs()...)
 		case AlwaysFetch[TFld]:
 			desc.constraints.alwaysFetch = sl.With(desc.constraints.alwaysFetch, constraints.qFs()...)
+		case Versioned[TFld]:
+			if flds := constraints.qFs(); (len(flds) != 1) || (desc.constraints.versioned != "") {
+				panic(desc.tableName + ": expected exactly one `Versioned` field")
+			} else if kind := desc.fieldTypeOfField(flds[0]).Kind(); (kind < reflect.Int) || (kind > reflect.Uint64) {
+				panic(desc.tableName + ": `Versioned` field '" + string(flds[0]) + "' must be of an integer type")
+			}
+			desc.constraints.versioned = constraints.qFs()[0]
+			desc.constraints.alwaysFetch = sl.With(desc.constraints.alwaysFetch, desc.constraints.versioned)
+		case SoftDelete:
+			desc.constraints.softDelete = &constraints
+		case Audited:
+			desc.constraints.audited = &constraints
+		case Notify:
+			desc.constraints.notify = &constraints
+		case Check[TFld]:
+			check := constraints.constraint(desc)
+			if _, exists := checkErrs[check.name]; exists {
+				panic(desc.tableName + ": duplicate `Check` '" + constraints.Name + "'")
+			}
+			checkErrs[check.name], desc.constraints.checks = check.err, append(desc.constraints.checks, check)
+		case FullText[TFld]:
+			for _, field_name := range constraints.qFs() {
+				if desc.fieldTypeOfField(field_name) != tyText {
+					panic(desc.tableName + ": `FullText` field '" + string(field_name) + "' must be of type `Text`")
+				}
+			}
+			desc.constraints.fullText = sl.With(desc.constraints.fullText, constraints.qFs()...)
+		case Migrations:
+			desc.mig.steps = append(desc.mig.steps, constraints...)
+		case AckDestructive:
+			desc.mig.ackDestructive = sl.With(desc.mig.ackDestructive, constraints...)
+		case ColConvs:
+			desc.mig.colConvs = append(desc.mig.colConvs, constraints...)
 		default:
 			panic(str.Fmt("%T %#v", constraints, constraints))
 		}
 	}
 	desc.mig.oldTableName, desc.mig.renamesOldColToNewField, desc.mig.constraintsChanged = oldTableName, renamesOldColToNewField, constraintsChanged
+	desc.mig.steps.ensureValid(desc.tableName)
+	desc.mig.colConvs.ensureValid(desc)
 	if (len(desc.cols) < 1) || (desc.cols[0] != ColID) {
 		panic(desc.tableName + ": first column must be '" + string(ColID))
 	} else if (len(desc.cols) < 2) || (desc.cols[1] != ColCreatedAt) {
@@ -476,8 +569,13 @@ func Ensure[TObj any, TFld q.Field](oldTableName string, renamesOldColToNewField
 		panic(desc.tableName + ": third column must be '" + string(ColModifiedAt))
 	} else if len(desc.cols) < 4 {
 		panic(desc.tableName + ": no custom columns")
+	} else if (desc.constraints.softDelete != nil) && sl.Has(desc.cols, ColDeletedAt) {
+		panic(desc.tableName + ": column '" + string(ColDeletedAt) + "' reserved for `SoftDelete`")
+	} else if (len(desc.constraints.fullText) > 0) && sl.Has(desc.cols, ColFullText) {
+		panic(desc.tableName + ": column '" + string(ColFullText) + "' reserved for `FullText`")
 	}
 	ensureDescs = append(ensureDescs, desc)
+	desc.registerConstraintErrs()
 	registerApiHandlers[TObj, TFld](desc)
 }
 
@@ -511,6 +609,12 @@ func Q[T any](it *T) q.Query {
 
 func doEnsureDbStructTables() {
 	var did_write_upd_trigger_func_yet, did_alterations bool
+	{
+		ctx := yoctx.NewCtxNonHttp(Cfg.YO_DB_CONN_TIMEOUT, false, "db.Mig: "+migsTableName)
+		ctx.TimingsNoPrintInDevMode, ctx.Db.PrintRawSqlInDevMode = true, false
+		migsEnsureTable(ctx)
+		ctx.OnDone(nil)
+	}
 	for _, desc := range ensureDescs {
 		if !IsDevMode {
 			yolog.Println("db: ensure %s as %s", desc.ty, desc.tableName)
@@ -529,18 +633,67 @@ func doEnsureDbStructTables() {
 			}
 			ctx.Timings.Step("createTable")
 			for _, stmt := range schemaCreateTable(desc, &did_write_upd_trigger_func_yet) {
-				_ = doExec(ctx, stmt, nil)
+				migsExecOrPrint(ctx, stmt, nil)
 			}
-
-		} else if stmts := schemaAlterTable(desc, cur_table); len(stmts) > 0 {
-			did_alterations = true
+			_ = migsRun(ctx, desc, true)
+			if desc.constraints.audited != nil {
+				for _, stmt := range schemaAuditStmts(desc) {
+					migsExecOrPrint(ctx, stmt, nil)
+				}
+			}
+			if desc.constraints.notify != nil {
+				for _, stmt := range schemaNotifyStmts(desc) {
+					migsExecOrPrint(ctx, stmt, nil)
+				}
+			}
+		} else {
+			stmts, convs, stmts_after_migs := schemaAlterTable(desc, cur_table)
 			for i, stmt := range stmts {
 				ctx.Timings.Step("alterTable " + str.FromInt(i+1) + "/" + str.FromInt(len(stmts)))
-				_ = doExec(ctx, stmt, nil)
+				migsExecOrPrint(ctx, stmt, nil)
+			}
+			for i := range convs {
+				convs[i].run(ctx, desc)
+			}
+			if is_table_rename {
+				migsRecordRename(ctx, desc.mig.oldTableName, desc.tableName)
+			}
+			did_migs := migsRun(ctx, desc, false)
+			for i, stmt := range stmts_after_migs {
+				ctx.Timings.Step("alterTable (post-migrations) " + str.FromInt(i+1) + "/" + str.FromInt(len(stmts_after_migs)))
+				migsExecOrPrint(ctx, stmt, nil)
+			}
+			did_alterations = did_alterations || did_migs || (len(stmts) > 0) || (len(convs) > 0) || (len(stmts_after_migs) > 0)
+			if is_table_rename {
+				for _, stmt := range schemaAuditRenameStmts(desc) {
+					migsExecOrPrint(ctx, stmt, nil)
+				}
+				migsExecOrPrint(ctx, schemaNotifyRenameDropTrigger(desc), nil)
+			}
+			if has_audit_table := (GetTable(ctx, desc.auditTableName()) != nil); (desc.constraints.audited != nil) && ((!has_audit_table) || !schemaHasAuditTrigger(ctx, desc)) {
+				did_alterations = true
+				for _, stmt := range schemaAuditStmts(desc) {
+					migsExecOrPrint(ctx, stmt, nil)
+				}
+			} else if (desc.constraints.audited == nil) && has_audit_table {
+				migsExecOrPrint(ctx, schemaAuditDropTrigger(desc), nil) // but keep the history around
+			}
+			if has_notify_trigger := schemaHasNotifyTrigger(ctx, desc); (desc.constraints.notify != nil) && !has_notify_trigger {
+				did_alterations = true
+				for _, stmt := range schemaNotifyStmts(desc) {
+					migsExecOrPrint(ctx, stmt, nil)
+				}
+			} else if (desc.constraints.notify == nil) && has_notify_trigger {
+				did_alterations = true
+				migsExecOrPrint(ctx, schemaNotifyDropTrigger(desc), nil)
 			}
 		}
 		ctx.OnDone(nil)
 	}
+	if Cfg.YO_DB_MIG_DRY_RUN {
+		yolog.Println("db: YO_DB_MIG_DRY_RUN done, exiting")
+		os.Exit(0)
+	}
 	if did_alterations && IsDevMode {
 		panic("performed DB alterations, redeploy & restart after cleaning up the provoking `Ensure` calls")
 	}
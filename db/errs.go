@@ -0,0 +1,95 @@
+package yodb
+
+import (
+	"reflect"
+
+	q "yo/db/query"
+	. "yo/srv"
+	. "yo/util"
+	"yo/util/str"
+)
+
+const (
+	ErrSetDbUnique  = "DbUnique"
+	ErrSetDbRef     = "DbRef" // both for writing a dangling `Ref` and for deleting an object still referenced via a `RefOnDelPrevent`
+	ErrSetDbNotNull = "DbNotNull"
+	ErrSetDbCheck   = "DbCheck"
+)
+
+// constraintErrs maps the Postgres names of unique and foreign-key constraints (and `table.column` for NOT NULL violations)
+// to their `Err`s, as `checkErrs` does for `Check`s
+var constraintErrs = map[string]Err{}
+
+func (me *structDesc) errSet(errSet string) string        { return errSet + "_" + me.ty.Name() }
+func (me *structDesc) err(errSet string, name string) Err { return Err(me.errSet(errSet) + "_" + name) }
+
+// registerConstraintErrs makes all constraint violations on `me` panic with their `Err`s (rather than
+// raw `*pgconn.PgError`s), and registers those into `KnownErrSets` for the DB APIs to depend on.
+func (me *structDesc) registerConstraintErrs() {
+	pkg_name, _, _ := str.Cut(me.ty.String(), ".")
+	register := func(errSet string, name string, constraintName string) {
+		if constraintName != "" {
+			constraintErrs[constraintName] = me.err(errSet, name)
+		}
+		KnownErrSets[me.errSet(errSet)] = append(KnownErrSets[me.errSet(errSet)], Err(name))
+		ErrsOwnPkg[me.err(errSet, name)] = pkg_name // for codegen alongside `me.ty`, not into `yodb`
+	}
+	for _, field_name := range me.constraints.uniques {
+		register(ErrSetDbUnique, string(field_name), pgObjName(me.tableName, string(me.colNameOfField(field_name)), "key"))
+	}
+	for i, field_name := range me.fields[3:] {
+		col_name, field_type := me.cols[3+i], me.fieldTypeOfField(field_name)
+		if isDbRefType(field_type) {
+			register(ErrSetDbRef, string(field_name), pgObjName(me.tableName, string(col_name), "fkey"))
+		} else if !sqlColNullable(field_type) {
+			register(ErrSetDbNotNull, string(field_name), me.tableName+"."+string(col_name))
+		}
+	}
+	for _, check := range me.constraints.checks {
+		if err_pref := me.errSet(ErrSetDbCheck) + "_"; str.Begins(string(check.err), err_pref) {
+			register(ErrSetDbCheck, str.TrimPref(string(check.err), err_pref), "") // already in `checkErrs`
+		} else { // custom `Check.Err`
+			ErrsNoPrefix = append(ErrsNoPrefix, check.err)
+		}
+	}
+}
+
+// errDepsOnWrite returns what creates and updates of `me` could fail with, for `ApiMethod.CouldFailWith`.
+func (me *structDesc) errDepsOnWrite() (ret []Err) {
+	for _, err_set := range []string{ErrSetDbUnique, ErrSetDbRef, ErrSetDbNotNull, ErrSetDbCheck} {
+		if len(KnownErrSets[me.errSet(err_set)]) > 0 {
+			ret = append(ret, Err(":"+me.errSet(err_set)))
+		}
+	}
+	for _, check := range me.constraints.checks {
+		if !str.Begins(string(check.err), me.errSet(ErrSetDbCheck)+"_") {
+			ret = append(ret, check.err)
+		}
+	}
+	return
+}
+
+// refsOnDelPrevent returns the `Ref` fields of `me` that prevent deletion of their `refDesc`s' objects.
+func (me *structDesc) refsOnDelPrevent() (ret []q.F) {
+	for _, field_name := range me.fields {
+		if field_type := me.fieldTypeOfField(field_name); isDbRefType(field_type) &&
+			(reflect.New(field_type).Interface().(refOnDel).onDelSql() == (RefOnDelPrevent{}).onDelSql()) {
+			ret = append(ret, field_name)
+		}
+	}
+	return
+}
+
+// pgObjName replicates Postgres' `makeObjectName` for the default names of implicitly-named constraints.
+func pgObjName(name1 string, name2 string, label string) string {
+	const max_len = 63
+	name1_len, name2_len := len(name1), len(name2)
+	for avail := max_len - (len(label) + 2); (name1_len + name2_len) > avail; {
+		if name1_len > name2_len {
+			name1_len--
+		} else {
+			name2_len--
+		}
+	}
+	return name1[:name1_len] + "_" + name2[:name2_len] + "_" + label
+}
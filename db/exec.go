@@ -11,6 +11,7 @@ import (
 	q "yo/db/query"
 	yojson "yo/json"
 	. "yo/util"
+	"yo/util/kv"
 	"yo/util/sl"
 	"yo/util/str"
 
@@ -44,8 +45,11 @@ func Ids[T any](ctx *Ctx, query q.Query) (ret sl.Of[I64]) {
 }
 
 func Exists[T any](ctx *Ctx, query q.Query) bool {
+	if inMem != nil {
+		return len(inMemFind[T](query, 1)) > 0
+	}
 	desc, args := desc[T](), dbArgs{}
-	result := doSelect[T](ctx,
+	result := doSelect[T](ctx, true,
 		new(sqlStmt).
 			selCols(desc, &[]q.C{ColID}, true).
 			fromAndJoinAndWhereAndOrderBy(desc, false, query, args).
@@ -63,12 +67,15 @@ func FindOne[T any](ctx *Ctx, query q.Query, orderBy ...q.OrderBy) *T {
 }
 
 func FindMany[T any](ctx *Ctx, query q.Query, maxResults int, onlyFields []q.F, orderBy ...q.OrderBy) []*T {
+	if inMem != nil {
+		return inMemFind[T](query, maxResults, orderBy...)
+	}
 	desc, args := desc[T](), dbArgs{}
 	cols := make([]q.C, len(onlyFields))
 	for i, field_name := range onlyFields {
 		cols[i] = desc.colNameOfField(field_name)
 	}
-	return doSelect[T](ctx,
+	return doSelect[T](ctx, true,
 		new(sqlStmt).
 			selCols(desc, &cols, false).
 			fromAndJoinAndWhereAndOrderBy(desc, false, query, args, orderBy...).
@@ -77,12 +84,21 @@ func FindMany[T any](ctx *Ctx, query q.Query, maxResults int, onlyFields []q.F,
 }
 
 func Each[T any](ctx *Ctx, query q.Query, maxResults int, orderBy []q.OrderBy, onRecord func(rec *T, enough *bool), onlyFields ...q.F) {
+	if inMem != nil {
+		var enough bool
+		for _, rec := range inMemFind[T](query, maxResults, orderBy...) {
+			if onRecord(rec, &enough); enough {
+				break
+			}
+		}
+		return
+	}
 	desc, args := desc[T](), dbArgs{}
 	cols := make([]q.C, len(onlyFields))
 	for i, field_name := range onlyFields {
 		cols[i] = desc.colNameOfField(field_name)
 	}
-	doStream[T](ctx,
+	doStream[T](ctx, true,
 		new(sqlStmt).
 			selCols(desc, &cols, false).
 			fromAndJoinAndWhereAndOrderBy(desc, false, query, args, orderBy...).
@@ -93,7 +109,7 @@ func Each[T any](ctx *Ctx, query q.Query, maxResults int, orderBy []q.OrderBy, o
 func Page[T any](ctx *Ctx, query q.Query, limit int, orderBy q.OrderBy, pageTok any) (resultsPage []*T, nextPageTok any) {
 	if pageTok != nil {
 		lt_or_gt := If(orderBy.Desc(), q.LessThan, q.GreaterThan)
-		query = lt_or_gt(orderBy.Col(), pageTok).And(query)
+		query = queryAnd(query, lt_or_gt(orderBy.Col(), pageTok))
 	}
 	resultsPage = FindMany[T](ctx, query, limit, nil, orderBy)
 	if len(resultsPage) > 0 {
@@ -105,12 +121,15 @@ func Page[T any](ctx *Ctx, query q.Query, limit int, orderBy q.OrderBy, pageTok
 }
 
 func Count[T any](ctx *Ctx, query q.Query, nonNullColumn q.C, distinct *q.C) int64 {
+	if inMem != nil {
+		return inMemCount[T](query, nonNullColumn, distinct)
+	}
 	desc, args := desc[T](), dbArgs{}
 	col := If((nonNullColumn != ""), nonNullColumn, ColID)
 	if distinct != nil {
 		col = *distinct
 	}
-	results := doSelect[int64](ctx,
+	results := doSelect[int64](ctx, true,
 		new(sqlStmt).
 			selCount(desc, col, distinct != nil).
 			fromAndJoinAndWhereAndOrderBy(desc, false, query, args),
@@ -122,12 +141,110 @@ func Delete[T any](ctx *Ctx, where q.Query) int64 {
 	if where == nil {
 		panic(ErrDbDelete_ExpectedQueryForDelete)
 	}
+	if inMem != nil {
+		return inMemDelete[T](where)
+	}
 	desc, args := desc[T](), dbArgs{}
-	result := doExec(ctx,
-		new(sqlStmt).
-			delete(desc.tableName).
-			fromAndJoinAndWhereAndOrderBy(desc, true, where, args),
-		args)
+	stmt := If(desc.constraints.softDelete != nil, new(sqlStmt).setDeleted(desc, true), new(sqlStmt).delete(desc.tableName))
+	result := doExecAudited(ctx, desc, stmt.fromAndJoinAndWhereAndOrderBy(desc, true, where, args), args)
+	num_rows_affected, err := result.RowsAffected()
+	if err != nil {
+		panic(err)
+	}
+	return num_rows_affected
+}
+
+// WithDeleted makes `query` (which may be `nil`) include the soft-deleted rows of a `SoftDelete` table, and those of any
+// `SoftDelete` tables joined for its dotted `Ref` fields (otherwise excluded as if gone). It must wrap the whole query:
+// further `And`s, `Or`s and `Not`s on the result are fine, but using it as a part of other queries panics.
+func WithDeleted[T any](query q.Query) q.Query {
+	if desc := desc[T](); (desc.constraints.softDelete == nil) && !sl.Any(desc.fields, func(it q.F) bool {
+		field_type := desc.fieldTypeOfField(it)
+		return isDbRefType(field_type) && (refDesc(field_type).constraints.softDelete != nil)
+	}) {
+		panic("WithDeleted on " + desc.tableName + " with neither SoftDelete nor Refs to SoftDelete tables")
+	}
+	return queryWithDeleted{query}
+}
+
+// queryWithDeleted is the `WithDeleted` flag, unwrapped by the statement builder (and the in-memory backend) via `withDeletedFrom`.
+type queryWithDeleted struct{ q.Query } // `Query` may be `nil`
+
+func (me queryWithDeleted) And(conds ...q.Query) q.Query {
+	return queryWithDeleted{queryAnd(me.Query, conds...)}
+}
+func (me queryWithDeleted) Or(conds ...q.Query) q.Query {
+	if me.Query == nil {
+		return me
+	}
+	return queryWithDeleted{me.Query.Or(conds...)}
+}
+func (me queryWithDeleted) Not() q.Query { return queryWithDeleted{me.Query.Not()} }
+func (me queryWithDeleted) Sql(*str.Buf, func(q.F) q.C, pgx.NamedArgs, bool) {
+	panic("WithDeleted must wrap the whole query, not be a part of it")
+}
+func (me queryWithDeleted) Eval(any, func(q.C) q.F) q.Query {
+	panic("WithDeleted must wrap the whole query, not be a part of it")
+}
+func (me queryWithDeleted) AllDottedFs() map[q.F][]string {
+	if me.Query == nil {
+		return nil
+	}
+	return me.Query.AllDottedFs()
+}
+
+func withDeletedFrom(query q.Query) (q.Query, bool) {
+	if with_deleted, is := query.(queryWithDeleted); is {
+		return with_deleted.Query, true
+	}
+	return query, false
+}
+
+// queryAnd is `query.And(conds...)` for a `query` that may be `nil`, and so keeps it outermost for any `WithDeleted` flag.
+func queryAnd(query q.Query, conds ...q.Query) q.Query {
+	if query == nil {
+		return q.AllTrue(conds...)
+	}
+	return query.And(conds...)
+}
+
+// Restore un-deletes the soft-deleted rows (of a `SoftDelete` table) matching `where`.
+func Restore[T any](ctx *Ctx, where q.Query) int64 {
+	inMemUnsupported("Restore")
+	desc, args := desc[T](), dbArgs{}
+	if desc.constraints.softDelete == nil {
+		panic("Restore on non-SoftDelete " + desc.tableName)
+	}
+	where = WithDeleted[T](queryAnd(where, q.C(desc.tableName+"."+string(ColDeletedAt)).NotEqual(nil)))
+	result := doExecAudited(ctx, desc, new(sqlStmt).setDeleted(desc, false).fromAndJoinAndWhereAndOrderBy(desc, true, where, args), args)
+	num_rows_affected, err := result.RowsAffected()
+	if err != nil {
+		panic(err)
+	}
+	return num_rows_affected
+}
+
+// SoftDeletePurgeableTables returns the table names of all `SoftDelete` tables with a `PurgeAfterDays` > 0.
+func SoftDeletePurgeableTables() (ret []string) {
+	for _, desc := range ensureDescs {
+		if (desc.constraints.softDelete != nil) && (desc.constraints.softDelete.PurgeAfterDays > 0) {
+			ret = append(ret, desc.tableName)
+		}
+	}
+	return
+}
+
+// PurgeSoftDeleted hard-deletes the rows of the given `SoftDelete` table soft-deleted longer than its `PurgeAfterDays` ago.
+func PurgeSoftDeleted(ctx *Ctx, tableName string) int64 {
+	inMemUnsupported("PurgeSoftDeleted")
+	idx := sl.IdxWhere(ensureDescs, func(it *structDesc) bool { return it.tableName == tableName })
+	if (idx < 0) || (ensureDescs[idx].constraints.softDelete == nil) || (ensureDescs[idx].constraints.softDelete.PurgeAfterDays <= 0) {
+		return 0
+	}
+	desc := ensureDescs[idx]
+	stmt := new(sqlStmt)
+	(*str.Buf)(stmt).WriteString("DELETE FROM " + desc.tableName + " WHERE " + string(ColDeletedAt) + " < (now() - make_interval(days => @D))")
+	result := doExecAudited(ctx, desc, stmt, dbArgs{"D": desc.constraints.softDelete.PurgeAfterDays})
 	num_rows_affected, err := result.RowsAffected()
 	if err != nil {
 		panic(err)
@@ -158,6 +275,10 @@ func Update[T any](ctx *Ctx, upd *T, where q.Query, skipNullsyFields bool, onlyF
 	if len(col_names) == 0 {
 		panic(ErrDbUpdate_ExpectedChangesForUpdate)
 	}
+	version_check := (desc.constraints.versioned != "") && !is_self_versioning
+	if version_check && (where != nil) { // a multi-row update could not detect conflicts per row
+		panic(ErrDbUpdate_ExpectedNoQueryForVersionedUpdate)
+	}
 
 	if where == nil { // ensuring the query has either the obj id...
 		id_maybe, _ := reflFieldValueOf(upd, FieldID).(I64)
@@ -189,17 +310,44 @@ func Update[T any](ctx *Ctx, upd *T, where q.Query, skipNullsyFields bool, onlyF
 		}
 	}
 
-	for i, col_name := range col_names {
-		args[string(col_name)] = col_vals[i]
+	// optimistic locking for `Versioned`: always checked, also if `upd`'s version is 0, as `where` was `nil` (see above)
+	var version_loaded int64
+	if version_check {
+		version_col := desc.colNameOfField(desc.constraints.versioned)
+		if rv := reflect.ValueOf(reflFieldValueOf(upd, desc.constraints.versioned)); rv.CanInt() {
+			version_loaded = rv.Int()
+		} else {
+			version_loaded = int64(rv.Uint())
+		}
+		query_and = version_col.Equal(version_loaded).And(query_and)
+		if idx := sl.IdxOf(col_names, version_col); idx >= 0 {
+			col_names, col_vals = sl.WithoutIdx(col_names, idx, false), sl.WithoutIdx(col_vals, idx, false)
+		}
+		col_names = append(col_names, version_col) // no arg, `sqlStmt.update` increments in-place
 	}
-	result := doExec(ctx,
-		new(sqlStmt).
-			update(desc, col_names...).
-			fromAndJoinAndWhereAndOrderBy(desc, true, where.And(query_and), args),
-		args)
-	num_rows_affected, err := result.RowsAffected()
-	if err != nil {
-		panic(err)
+
+	var num_rows_affected int64
+	if inMem != nil {
+		num_rows_affected = inMemUpdate[T](where.And(query_and), col_names, col_vals)
+	} else {
+		for i, col_name := range col_names[:len(col_vals)] {
+			args[string(col_name)] = col_vals[i]
+		}
+		result := doExecAudited(ctx, desc,
+			new(sqlStmt).
+				update(desc, col_names...).
+				fromAndJoinAndWhereAndOrderBy(desc, true, where.And(query_and), args),
+			args)
+		var err error
+		if num_rows_affected, err = result.RowsAffected(); err != nil {
+			panic(err)
+		}
+	}
+	if version_check {
+		if num_rows_affected == 0 {
+			panic(ErrDbUpdate_Conflict)
+		}
+		reflFieldSetInt(upd, desc.constraints.versioned, version_loaded+1)
 	}
 	return num_rows_affected
 }
@@ -208,9 +356,13 @@ func CreateOne[T any](ctx *Ctx, rec *T) (ret I64) {
 	if self_versioning, _ := ((any)(rec)).(SelfVersioningObj); self_versioning != nil {
 		_, _ = self_versioning.OnBeforeStoring(true)
 	}
+	if inMem != nil {
+		return inMemInsert(rec)
+	}
 	desc := desc[T]()
 	args := dbArgsFillForInsertNormal[T](desc, make(dbArgs, len(desc.fields)), []*T{rec})
-	result := doSelect[int64](ctx, new(sqlStmt).insert(desc, 1, false, true), args, 1)
+	var result []*int64
+	audited(ctx, desc, func() { result = doSelect[int64](ctx, false, new(sqlStmt).insert(desc, 1, false, true), args, 1) })
 	if (len(result) > 0) && (result[0] != nil) {
 		ret = I64(*result[0])
 	}
@@ -230,6 +382,71 @@ func Upsert[T any](ctx *Ctx, rec *T) {
 	upOrInsert[T](ctx, true, rec)
 }
 
+// UpsertMany inserts all `recs` in a single statement. Those conflicting with an existing row on `conflictOn`
+// (which must be a `Unique` field) instead update that row's `overwrite` fields, or if none are given,
+// all its non-`Unique`, non-`ReadOnly` fields. If that leaves nothing to overwrite, conflicting `recs` are skipped.
+// Of several `recs` with the same `conflictOn` value, only the last one is used. On `SoftDelete` tables, conflicting
+// soft-deleted rows are restored (then overwritten as above), rather than updated while staying invisible.
+func UpsertMany[T any](ctx *Ctx, conflictOn q.F, overwrite []q.F, recs ...*T) {
+	if len(recs) == 0 {
+		return
+	}
+	desc := desc[T]()
+	if !sl.Has(desc.constraints.uniques, conflictOn) {
+		panic("UpsertMany on " + desc.tableName + ": conflict target '" + string(conflictOn) + "' is not a Unique field")
+	}
+	if len(overwrite) == 0 {
+		overwrite = sl.Where(desc.fields[numStdCols:], func(it q.F) bool {
+			return !(sl.Has(desc.constraints.uniques, it) || sl.Has(desc.constraints.readOnly, it))
+		})
+	}
+	overwrite = sl.Where(overwrite, func(it q.F) bool { return it != desc.constraints.versioned })
+	for _, field_name := range overwrite {
+		if (!sl.Has(desc.fields[numStdCols:], field_name)) || sl.Has(desc.constraints.readOnly, field_name) || (field_name == conflictOn) {
+			panic("UpsertMany on " + desc.tableName + ": cannot overwrite '" + string(field_name) + "'")
+		}
+	}
+	if (desc.constraints.versioned != "") && (len(overwrite) > 0) {
+		overwrite = append(overwrite, desc.constraints.versioned)
+	}
+	{ // Postgres rejects an `ON CONFLICT DO UPDATE` affecting the same row twice, so dedupe (keeping the last of each)
+		idx_last := make(map[any]int, len(recs))
+		for i, rec := range recs {
+			idx_last[upsertConflictKey(rec, conflictOn)] = i
+		}
+		if len(idx_last) < len(recs) {
+			deduped := make([]*T, 0, len(idx_last))
+			for i, rec := range recs {
+				if idx_last[upsertConflictKey(rec, conflictOn)] == i {
+					deduped = append(deduped, rec)
+				}
+			}
+			recs = deduped
+		}
+	}
+	if _, is_self_versioning := any(recs[0]).(SelfVersioningObj); is_self_versioning {
+		for i := range recs {
+			self_versioning := any(recs[i]).(SelfVersioningObj)
+			_, _ = self_versioning.OnBeforeStoring(false)
+		}
+	}
+	if inMem != nil {
+		inMemUpsert([]q.F{conflictOn}, overwrite, recs...)
+		return
+	}
+	args := dbArgsFillForInsertViaUnnest(desc, make(dbArgs, len(desc.fields)), recs)
+	_ = doExecAudited(ctx, desc, new(sqlStmt).insertViaUnnest(desc, false).
+		insertViaUnnestUpsertAppendum(desc, desc.colNameOfField(conflictOn), sl.As(overwrite, desc.colNameOfField)), args)
+}
+
+// upsertConflictKey is the `conflictOn` value of `rec` in map-key form (`Unique`s aren't all comparable Go values).
+func upsertConflictKey[T any](rec *T, conflictOn q.F) any {
+	if ref, _ := reflFieldValueOf(rec, conflictOn).(dbRef); ref != nil {
+		return ref.Id()
+	}
+	return string(yojson.From(reflFieldValueOf(rec, conflictOn), false))
+}
+
 func upOrInsert[T any](ctx *Ctx, upsert bool, recs ...*T) {
 	if len(recs) == 0 {
 		return
@@ -243,36 +460,49 @@ func upOrInsert[T any](ctx *Ctx, upsert bool, recs ...*T) {
 			_, _ = self_versioning.OnBeforeStoring(!upsert)
 		}
 	}
-	if upsert {
+	if inMem != nil {
+		inMemUpsert(If(upsert, desc.constraints.uniques, nil), sl.Without(desc.fields[numStdCols:], desc.constraints.uniques...), recs...)
+	} else if upsert {
 		args = dbArgsFillForInsertNormal(desc, args, recs)
-		_ = doExec(ctx, new(sqlStmt).insert(desc, len(recs), true, false), args)
+		_ = doExecAudited(ctx, desc, new(sqlStmt).insert(desc, len(recs), true, false), args)
 	} else {
 		args = dbArgsFillForInsertViaUnnest(desc, args, recs)
-		_ = doExec(ctx, new(sqlStmt).insertViaUnnest(desc, false), args)
+		_ = doExecAudited(ctx, desc, new(sqlStmt).insertViaUnnest(desc, false), args)
 	}
 }
 
+func doExecAudited(ctx *Ctx, desc *structDesc, stmt *sqlStmt, args dbArgs) (ret sql.Result) {
+	audited(ctx, desc, func() { ret = doExec(ctx, stmt, args) })
+	return
+}
+
 func doExec(ctx *Ctx, stmt *sqlStmt, args dbArgs) sql.Result {
 	sql_raw := str.TrimSuff(stmt.String(), ",")
 	do_exec := DB.ExecContext
 	if ctx.Db.Tx != nil {
 		do_exec = ctx.Db.Tx.ExecContext
 	}
+	ctx.Db.ReadYourWrites = true
 
 	args = dbArgsCleanUpForPgx(args)
 	printIfDbgMode(ctx, sql_raw, args)
+	time_started := time.Now()
 	result, err := do_exec(ctx, sql_raw, args)
 	if err != nil {
-		panic(err)
+		dbStatsRecord(ctx, sql_raw, args, time_started, 0, 0, false)
+		panic(dbErrFrom(err))
 	}
+	num_rows, _ := result.RowsAffected()
+	dbStatsRecord(ctx, sql_raw, args, time_started, 0, num_rows, false)
+	dbExplainAnalyzeIfFlagged(ctx, sql_raw, args, false)
 	return result
 }
 
-func doSelect[T any](ctx *Ctx, stmt *sqlStmt, args dbArgs, maxResults int, cols ...q.C) (ret []*T) {
+func doSelect[T any](ctx *Ctx, isRead bool, stmt *sqlStmt, args dbArgs, maxResults int, cols ...q.C) (ret []*T) {
 	if maxResults > 0 {
 		ret = make([]*T, 0, Clamp(4, 128, maxResults))
 	}
-	doStream[T](ctx, stmt, func(rec *T, endNow *bool) {
+	doStream[T](ctx, isRead, stmt, func(rec *T, endNow *bool) {
 		if ret = append(ret, rec); (maxResults > 0) && (len(ret) == maxResults) {
 			*endNow = true
 		}
@@ -280,25 +510,35 @@ func doSelect[T any](ctx *Ctx, stmt *sqlStmt, args dbArgs, maxResults int, cols
 	return
 }
 
-func doStream[T any](ctx *Ctx, stmt *sqlStmt, onRecord func(*T, *bool), args dbArgs, cols ...q.C) {
+// doStream runs `stmt` on `dbForReads` if `isRead` (as only the caller knows, eg. not for `INSERT ... RETURNING`s), else on the primary `DB`.
+func doStream[T any](ctx *Ctx, isRead bool, stmt *sqlStmt, onRecord func(*T, *bool), args dbArgs, cols ...q.C) {
 	sql_raw := stmt.String()
-	do_query := DB.QueryContext
+	do_query := If(isRead, dbForReads(ctx), DB).QueryContext
 	if ctx.Db.Tx != nil {
 		do_query = ctx.Db.Tx.QueryContext
 	}
+	if !isRead {
+		ctx.Db.ReadYourWrites = true
+	}
 
 	args = dbArgsCleanUpForPgx(args)
 	printIfDbgMode(ctx, sql_raw, args)
+	time_started, time_in_callbacks, num_rows := time.Now(), time.Duration(0), int64(0)
+	defer func() { // also if failed
+		dbStatsRecord(ctx, sql_raw, args, time_started, time_in_callbacks, num_rows, isRead)
+		dbExplainAnalyzeIfFlagged(ctx, sql_raw, args, isRead)
+	}()
 	rows, err := do_query(ctx, sql_raw, args)
 	if rows != nil {
 		defer rows.Close()
 	}
 	if err != nil {
-		panic(err)
+		panic(dbErrFrom(err))
 	}
 	var struct_desc *structDesc
 	_, is_i64_returned_from_insert_or_count := ((any)(new(T))).(*int64)
-	if !is_i64_returned_from_insert_or_count {
+	_, is_dict_of_cols := ((any)(new(T))).(*kv.Any) // for `Aggregate`s, keyed by the (then mandatory) `cols`
+	if (!is_i64_returned_from_insert_or_count) && !is_dict_of_cols {
 		struct_desc = desc[T]()
 	}
 	if (len(cols) == 0) && (struct_desc != nil) {
@@ -311,12 +551,19 @@ func doStream[T any](ctx *Ctx, stmt *sqlStmt, onRecord func(*T, *bool), args dbA
 			if err := rows.Scan(&rec); err != nil {
 				panic(err)
 			}
-			abort = true
+			abort, num_rows = true, 1
 			onRecord(&rec, &abort)
 			break
 		}
-		rv, col_scanners := reflect.ValueOf(&rec).Elem(), make([]any, len(cols))
+		rv, col_scanners, dict_vals := reflect.ValueOf(&rec).Elem(), make([]any, len(cols)), []any(nil)
+		if is_dict_of_cols {
+			dict_vals = make([]any, len(cols))
+		}
 		for i, col_name := range cols {
+			if is_dict_of_cols {
+				col_scanners[i] = &dict_vals[i]
+				continue
+			}
 			field_name := struct_desc.fieldNameOfCol(col_name)
 			field := rv.FieldByName(string(field_name))
 			field_t, _ := struct_desc.ty.FieldByName(string(field_name))
@@ -335,25 +582,37 @@ func doStream[T any](ctx *Ctx, stmt *sqlStmt, onRecord func(*T, *bool), args dbA
 					json_db_val = dummy.initOther(unsafe.Pointer(unsafe_addr))
 				}
 			} else if is_db_json_obj_type {
-				ptr := field.Addr().Interface()
-				json_db_val = ptr.(dbJsonValue)
-				json_db_val.init(ptr)
+				if field_t.IsExported() {
+					ptr := field.Addr().Interface()
+					json_db_val = ptr.(dbJsonValue)
+					json_db_val.init(ptr)
+				} else {
+					json_db_val = reflect.New(field.Type()).Interface().(dbJsonValue).initOther(field.Addr().UnsafePointer())
+				}
 			}
 			col_scanners[i] = scanner{ptr: unsafe_addr, jsonDbVal: json_db_val, ty: field.Type()}
 		}
 		if err = rows.Scan(col_scanners...); err != nil {
 			panic(err)
 		}
+		if dict, _ := ((any)(&rec)).(*kv.Any); dict != nil {
+			*dict = make(kv.Any, len(cols))
+			for i, col_name := range cols {
+				(*dict)[string(col_name)] = dict_vals[i]
+			}
+		}
 		if self_versioning, _ := ((any)(&rec)).(SelfVersioningObj); self_versioning != nil {
 			self_versioning.OnAfterLoaded()
 		}
+		num_rows++
+		time_callback_started := time.Now()
 		onRecord(&rec, &abort)
-		if abort {
+		if time_in_callbacks += time.Since(time_callback_started); abort {
 			break
 		}
 	}
 	if err = rows.Err(); err != nil {
-		panic(err)
+		panic(dbErrFrom(err))
 	}
 }
 
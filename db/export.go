@@ -0,0 +1,188 @@
+package yodb
+
+import (
+	"bufio"
+	"encoding/csv"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	. "yo/cfg"
+	. "yo/ctx"
+	q "yo/db/query"
+	yojson "yo/json"
+	. "yo/util"
+	"yo/util/sl"
+	"yo/util/str"
+)
+
+type ExportFormat string
+
+const (
+	ExportFormatCsv    ExportFormat = "csv"    // with a header row of the field names
+	ExportFormatNdjson ExportFormat = "ndjson" // one JSON object per line
+)
+
+const exportFlushEveryNumRecs = 1000
+
+func (me ExportFormat) contentType() string {
+	return If(me == ExportFormatNdjson, "application/x-ndjson", "text/csv; charset=utf-8")
+}
+
+// Export streams all `T`s matching `query` (all if `nil`, at most `maxResults` if > 0) in `orderBy` order into `w`,
+// flushing periodically rather than buffering the whole result. Only the given `fields` are written (all exported ones if none),
+// which must all be exported. Unexported fields are never written, as they're by convention not for the outside world.
+func Export[T any](ctx *Ctx, w io.Writer, format ExportFormat, query q.Query, maxResults int, orderBy []q.OrderBy, fields ...q.F) {
+	format, fields = exportArgs[T](format, fields)
+	export[T](ctx, w, format, query, maxResults, orderBy, fields)
+}
+
+// exportArgs validates `format` and `fields` (panicking with `ErrSetExport` errs) and returns them with their defaults filled in.
+func exportArgs[T any](format ExportFormat, fields []q.F) (ExportFormat, []q.F) {
+	desc := desc[T]()
+	if format == "" {
+		format = ExportFormatCsv
+	} else if (format != ExportFormatCsv) && (format != ExportFormatNdjson) {
+		panic(ErrExport_ExpectedCsvOrNdjsonFormat)
+	}
+	if len(fields) == 0 {
+		fields = sl.Where(desc.fields, func(it q.F) bool { field, _ := desc.ty.FieldByName(string(it)); return field.IsExported() })
+	}
+	for _, field_name := range fields {
+		if field, _ := desc.ty.FieldByName(string(field_name)); (!sl.Has(desc.fields, field_name)) || !field.IsExported() {
+			panic(ErrExport_ExpectedExportedFields)
+		}
+	}
+	return format, fields
+}
+
+func export[T any](ctx *Ctx, w io.Writer, format ExportFormat, query q.Query, maxResults int, orderBy []q.OrderBy, fields []q.F) {
+	flusher, _ := w.(http.Flusher)
+	var csv_w *csv.Writer
+	var ndjson_w *bufio.Writer
+	flush := func() {
+		if csv_w != nil {
+			csv_w.Flush()
+			if err := csv_w.Error(); err != nil {
+				panic(err)
+			}
+		} else if err := ndjson_w.Flush(); err != nil {
+			panic(err)
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+	if format == ExportFormatCsv {
+		csv_w = csv.NewWriter(w)
+		if err := csv_w.Write(sl.As(fields, func(it q.F) string { return string(it) })); err != nil {
+			panic(err)
+		}
+	} else {
+		ndjson_w = bufio.NewWriter(w)
+	}
+
+	num_recs, csv_rec := 0, make([]string, len(fields))
+	Each[T](ctx, query, maxResults, orderBy, func(rec *T, _ *bool) {
+		rv := reflect.ValueOf(rec)
+		if csv_w != nil {
+			for i, field_name := range fields {
+				csv_rec[i] = exportCsvVal(rv.Elem().FieldByName(string(field_name)).Addr().Interface())
+			}
+			if err := csv_w.Write(csv_rec); err != nil {
+				panic(err)
+			}
+		} else {
+			_ = ndjson_w.WriteByte('{')
+			for i, field_name := range fields {
+				if i > 0 {
+					_ = ndjson_w.WriteByte(',')
+				}
+				_, _ = ndjson_w.WriteString(str.Q(string(field_name)))
+				_ = ndjson_w.WriteByte(':')
+				_, _ = ndjson_w.Write(yojson.From(rv.Elem().FieldByName(string(field_name)).Addr().Interface(), false))
+			}
+			_, _ = ndjson_w.WriteString("}\n")
+		}
+		if num_recs++; (num_recs % exportFlushEveryNumRecs) == 0 {
+			flush()
+		}
+	}, fields...)
+	flush()
+}
+
+func exportCsvVal(fieldPtr any) string {
+	switch it := fieldPtr.(type) {
+	case *Text:
+		return string(*it)
+	case **DateTime:
+		if *it == nil {
+			return ""
+		}
+		return (*it).Time().Format(time.RFC3339Nano)
+	case dbRef:
+		return If(it.Id() == 0, "", str.FromI64(int64(it.Id()), 10))
+	}
+	switch rv := reflect.ValueOf(fieldPtr).Elem(); rv.Kind() {
+	case reflect.Bool, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Float32, reflect.Float64:
+		return str.FmtV(rv.Interface())
+	}
+	json := string(yojson.From(fieldPtr, false)) // `Bytes` (base64), `Arr`s and JSON types
+	return If(json == "null", "", json)
+}
+
+// ExportToHttp is `Export` straight into the `ctx.Http.Resp` download, so the (API) handler must not write anything else.
+// Bad `format` or `fields` fail before any response writing (with `ErrSetExport` errs), later failures cut off the download.
+// The streaming gets `YO_API_EXPORT_TIMEOUT` if set, else whatever remains of `ctx`'s own timeout (usually `YO_API_IMPL_TIMEOUT`).
+func ExportToHttp[T any](ctx *Ctx, format ExportFormat, query q.Query, maxResults int, orderBy []q.OrderBy, fields ...q.F) {
+	format, fields = exportArgs[T](format, fields)
+	ctx.Http.Resp.Header().Set("Content-Type", format.contentType())
+	ctx.Http.Resp.Header().Set("Content-Disposition", "attachment; filename=\""+desc[T]().ty.Name()+"."+string(format)+"\"")
+	ctx.HttpOnPreWriteResponse()
+	if Cfg.YO_API_EXPORT_TIMEOUT > 0 {
+		ctx_orig := ctx
+		ctx = ctx.CopyButWith(Cfg.YO_API_EXPORT_TIMEOUT, false)
+		defer func() { // not `OnDone`: failures are for the original `ctx`'s, and so are the stats (also if failed)
+			ctx.Cancel()
+			ctx_orig.Db.Stats.Add(&ctx.Db.Stats)
+		}()
+	}
+	export[T](ctx, ctx.Http.Resp, format, query, maxResults, orderBy, fields)
+}
+
+// ExportToFile is `Export` into the file `fileName` in the `STATIC_FILE_STORAGE_DIRS` dir `storageDirName`, for use from jobs.
+// The file is first written under a temporary name, so it never appears incomplete. Returns the file path.
+func ExportToFile[T any](ctx *Ctx, storageDirName string, fileName string, format ExportFormat, query q.Query, maxResults int, orderBy []q.OrderBy, fields ...q.F) string {
+	dir_path := Cfg.STATIC_FILE_STORAGE_DIRS[storageDirName]
+	if dir_path == "" {
+		panic("ExportToFile: no such STATIC_FILE_STORAGE_DIRS entry '" + storageDirName + "'")
+	} else if (fileName == "") || (filepath.Base(fileName) != fileName) {
+		panic("ExportToFile: invalid file name '" + fileName + "'")
+	}
+	file_path := filepath.Join(dir_path, fileName)
+	file, err := os.CreateTemp(dir_path, "."+fileName+".*")
+	if err != nil {
+		panic(err)
+	}
+	Try(func() {
+		Export[T](ctx, file, format, query, maxResults, orderBy, fields...)
+		if err = file.Sync(); err != nil {
+			panic(err)
+		}
+	}, func(fail any) {
+		_ = file.Close()
+		FsDelFile(file.Name())
+		panic(fail)
+	})
+	if err = file.Close(); err != nil {
+		panic(err)
+	}
+	if err = os.Rename(file.Name(), file_path); err != nil {
+		FsDelFile(file.Name())
+		panic(err)
+	}
+	return file_path
+}
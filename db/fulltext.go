@@ -0,0 +1,56 @@
+package yodb
+
+import (
+	. "yo/ctx"
+	q "yo/db/query"
+	"yo/util/sl"
+	"yo/util/str"
+)
+
+// ColFullText is the generated `tsvector` column of `FullText` tables, not a struct field.
+const ColFullText = q.C("fts_")
+
+// FullTextConfig is the PG text search config used for all `FullText` columns and matches.
+// Changing it requires passing `constraintsChanged` to `Ensure` to re-generate the `ColFullText` columns.
+var FullTextConfig = "simple"
+
+// FullText lists the `Text` fields to search via `FullTextMatch` and `FullTextSearch`, kept in a generated and
+// GIN-indexed `ColFullText` column. Changing the field list requires passing `constraintsChanged` to `Ensure`.
+type FullText[T q.Field] []T
+
+func (me FullText[TFld]) qFs() []q.F { return sl.As(me, func(it TFld) q.F { return it.F() }) }
+
+func schemaFullTextColDecl(desc *structDesc) string {
+	if !str.IsLo(FullTextConfig) {
+		panic("invalid FullTextConfig: '" + FullTextConfig + "'")
+	}
+	return string(ColFullText) + " tsvector GENERATED ALWAYS AS (to_tsvector('" + FullTextConfig + "', " +
+		str.Join(sl.As(desc.constraints.fullText, func(it q.F) string {
+			return "coalesce(" + string(desc.colNameOfField(it)) + ", '')"
+		}), " || ' ' || ") + ")) STORED"
+}
+
+// FullTextMatch is the `q.Query` matching rows of a `FullText` table against the web-search-syntax `searchTerms`.
+func FullTextMatch(searchTerms string) q.Query {
+	return q.TsMatch(ColFullText, FullTextConfig, searchTerms)
+}
+
+// FullTextRank is the match rank of rows of a `FullText` table against `searchTerms`, to order by (best first if `.Desc()`)
+// in `FindMany`, `Paged` etc. alongside a `FullTextMatch` of the same `searchTerms`.
+func FullTextRank(searchTerms string) q.Sortable {
+	return q.TsRank(ColFullText, FullTextConfig, searchTerms)
+}
+
+// FullTextSearch is like `FindMany` with `query` narrowed by `FullTextMatch(searchTerms)`, but its results are ordered by match rank, best first.
+func FullTextSearch[T any](ctx *Ctx, searchTerms string, query q.Query, maxResults int, onlyFields ...q.F) []*T {
+	inMemUnsupported("FullTextSearch")
+	desc, args := desc[T](), dbArgs{}
+	if len(desc.constraints.fullText) == 0 {
+		panic("FullTextSearch on non-FullText " + desc.tableName)
+	}
+	cols := []q.C(sl.As(onlyFields, desc.colNameOfField))
+	stmt := new(sqlStmt).
+		selCols(desc, &cols, false).
+		fromAndJoinAndWhereAndOrderBy(desc, false, queryAnd(query, FullTextMatch(searchTerms)), args, FullTextRank(searchTerms).Desc())
+	return doSelect[T](ctx, true, stmt.limit(maxResults), args, maxResults, cols...)
+}
@@ -0,0 +1,32 @@
+package yodb
+
+import (
+	"testing"
+
+	q "yo/db/query"
+	"yo/util/str"
+)
+
+type testFullTextThing struct {
+	Id     I64
+	DtMade *DateTime
+	DtMod  *DateTime
+
+	Title Text
+}
+
+func TestFullTextRankStmts(t *testing.T) {
+	desc, args := desc[testFullTextThing](), dbArgs{}
+	stmt := new(sqlStmt).fromAndJoinAndWhereAndOrderBy(desc, false, FullTextMatch("foo bar"), args, FullTextRank("foo bar").Desc(), FieldID.Asc())
+	if expect := " ORDER BY ts_rank(fts_,websearch_to_tsquery(@A2 ,@A3 )) DESC, test_full_text_thing_.id_ ASC"; !str.Ends(stmt.String(), expect) {
+		t.Errorf("expected %s, got: %s", expect, stmt.String())
+	}
+	if (args["A2"] != FullTextConfig) || (args["A3"] != "foo bar") {
+		t.Errorf("expected rank args, got: %v", args)
+	}
+
+	after := pageTokQuery(desc, []q.OrderBy{FullTextRank("foo").Desc(), FieldID.Asc()}, []any{0.5, I64(22)})
+	if sql := q.SqlReprForDebugging(after); !str.Begins(sql, "(((ts_rank(fts_,websearch_to_tsquery(@A0 ,@A1 )) < @A2 )) OR ((((ts_rank(fts_,websearch_to_tsquery(@A3 ,@A4 )) = @A5 ))") {
+		t.Errorf("expected ts_rank keyset condition, got: %s", sql)
+	}
+}
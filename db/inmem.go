@@ -0,0 +1,452 @@
+package yodb
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+	"unsafe"
+
+	q "yo/db/query"
+	yojson "yo/json"
+	. "yo/util"
+	"yo/util/sl"
+	"yo/util/str"
+)
+
+var inMem *inMemDb
+
+type inMemDb struct {
+	sync.Mutex
+	tables map[*structDesc]*inMemTable
+}
+
+type inMemTable struct {
+	lastId  I64
+	rows    map[I64]reflect.Value // each a `*T`
+	deleted map[I64]bool          // for `SoftDelete` tables
+}
+
+// InitInMem is the `InitAndConnectAndMigrateAndMaybeCodegen` alternative for unit tests: no DB connection, no schema work,
+// instead all `Ensure`d tables live in Go maps, starting out empty (also on repeat calls). `Unique`s, `Check`s and `Ref` on-delete semantics
+// are enforced, `Ctx.DbTx` is a no-op. Supported are `ById`, `Ids`, `Exists`, `FindOne`, `FindMany`, `Each`, `Count`, `Page`, `Paged`,
+// `CreateOne`, `CreateMany`, `Update`, `Upsert`, `UpsertMany` and `Delete`, with queries evaluated via `q.Query.Eval` (so no dotted/joined fields).
+// Limits: `Restore`, `History`, `Aggregate`, `FullTextSearch`, `FullTextRank` orderings and `PurgeSoftDeleted` panic as unsupported,
+// `onlyFields` are ignored (all fields are always loaded), and neither `Audited` history rows nor `Notify` change feeds are produced.
+func InitInMem() (dbStructs []reflect.Type) {
+	pageTokSignKeyEnsure()
+	inMem = &inMemDb{tables: map[*structDesc]*inMemTable{}}
+	for _, desc := range ensureDescs {
+		dbStructs = append(dbStructs, desc.ty)
+	}
+	inited = true
+	return
+}
+
+func inMemUnsupported(what string) {
+	if inMem != nil {
+		panic(what + ": not supported by yodb's in-memory backend")
+	}
+}
+
+func (me *inMemDb) table(desc *structDesc) *inMemTable {
+	tbl := me.tables[desc]
+	if tbl == nil {
+		tbl = &inMemTable{rows: map[I64]reflect.Value{}, deleted: map[I64]bool{}}
+		me.tables[desc] = tbl
+	}
+	return tbl
+}
+
+// inMemField returns the settable (even if unexported) field of `row`, a `*T`
+func inMemField(row reflect.Value, fieldName q.F) reflect.Value {
+	field := row.Elem().FieldByName(string(fieldName))
+	return reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem()
+}
+
+// inMemVal normalizes `field` into what Postgres would compare: `nil` for NULLs, ids for `Ref`s, `time.Time`s for `DateTime`s.
+func inMemVal(field reflect.Value) any {
+	if isDbRefType(field.Type()) {
+		return If[any](field.Interface().(dbRef).Id() == 0, nil, field.Interface().(dbRef).Id())
+	}
+	for field.Kind() == reflect.Pointer {
+		if field.IsNil() {
+			return nil
+		}
+		field = field.Elem()
+	}
+	if (field.Kind() == reflect.Slice) || (field.Kind() == reflect.Map) {
+		if field.IsNil() {
+			return nil
+		}
+	} else if field.Type() == tyDateTime.Elem() {
+		return time.Time(field.Interface().(DateTime))
+	}
+	return field.Interface()
+}
+
+// inMemNull is the `inMemEvalVal` of NULLs: unlike `nil`, it's never less or greater than (nor equal to) any non-NULL value
+// in `q.Query.Eval`, and it's `null` in JSON.
+type inMemNull struct{}
+
+func (inMemNull) MarshalJSON() ([]byte, error) { return yojson.TokNull, nil }
+
+// inMemEvalVal normalizes both field values and query operands for `q.Query.Eval`, so that its comparisons do as Postgres
+// would (without changing them for `q.Query.Eval` over plain structs): `inMemNull`s for NULLs, ids for `Ref`s, Unix
+// nanoseconds for `DateTime`s and `time.Time`s, and pointees for pointers.
+func inMemEvalVal(value any) any {
+	if rv := reflect.ValueOf(value); rv.IsValid() {
+		value = inMemVal(rv)
+	}
+	if value == nil {
+		return inMemNull{}
+	} else if t, is := value.(time.Time); is {
+		return t.UnixNano()
+	}
+	return value
+}
+
+// inMemRow is what the in-memory backend passes to `q.Query.Eval`: its field values and the query operands are `inMemEvalVal`s.
+type inMemRow struct{ reflect.Value }
+
+func (me inMemRow) FieldValue(fieldName q.F) any {
+	return inMemEvalVal(inMemField(me.Value, fieldName).Interface())
+}
+func (inMemRow) OperandValue(value any) any { return inMemEvalVal(value) }
+
+// inMemCopy returns a deep copy of `rv`, so that rows stored and rows returned never share slices, maps or pointees
+// with each other or with callers' values (as with a real DB). `Ref`s keep only their id, `IsJsonOf`s point to their copy.
+func inMemCopy(rv reflect.Value) reflect.Value {
+	if rv.CanAddr() { // also lifts the read-only-ness of values obtained via unexported fields
+		rv = reflect.NewAt(rv.Type(), unsafe.Pointer(rv.UnsafeAddr())).Elem()
+	} else {
+		addressable := reflect.New(rv.Type()).Elem()
+		addressable.Set(rv)
+		rv = addressable
+	}
+	ret := reflect.New(rv.Type()).Elem()
+	ret.Set(rv)
+	switch rv.Kind() {
+	case reflect.Pointer:
+		if !rv.IsNil() {
+			ptr := reflect.New(rv.Type().Elem())
+			ptr.Elem().Set(inMemCopy(rv.Elem()))
+			ret.Set(ptr)
+		}
+	case reflect.Interface:
+		if !rv.IsNil() {
+			ret.Set(inMemCopy(rv.Elem()))
+		}
+	case reflect.Slice:
+		if !rv.IsNil() {
+			slice := reflect.MakeSlice(rv.Type(), rv.Len(), rv.Len())
+			for i := range rv.Len() {
+				slice.Index(i).Set(inMemCopy(rv.Index(i)))
+			}
+			ret.Set(slice)
+		}
+	case reflect.Map:
+		if !rv.IsNil() {
+			dict := reflect.MakeMapWithSize(rv.Type(), rv.Len())
+			for iter := rv.MapRange(); iter.Next(); {
+				dict.SetMapIndex(inMemCopy(iter.Key()), inMemCopy(iter.Value()))
+			}
+			ret.Set(dict)
+		}
+	case reflect.Array:
+		for i := range rv.Len() {
+			ret.Index(i).Set(inMemCopy(rv.Index(i)))
+		}
+	case reflect.Struct:
+		if isDbRefType(rv.Type()) {
+			ret.Addr().Interface().(interface{ SetId(I64) }).SetId(rv.Interface().(dbRef).Id())
+		} else if !rv.Type().ConvertibleTo(ReflTypeTime) {
+			for i := range rv.NumField() {
+				field := ret.Field(i)
+				field = reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem()
+				if json_self, _ := field.Addr().Interface().(dbJsonValue); (json_self != nil) && (field.Kind() == reflect.Struct) {
+					json_self.init(ret.Addr().Interface())
+				} else {
+					field.Set(inMemCopy(rv.Field(i)))
+				}
+			}
+		}
+	}
+	return ret
+}
+
+func inMemSet(field reflect.Value, value any) {
+	rv := reflect.ValueOf(value)
+	switch {
+	case isDbRefType(field.Type()):
+		var id I64
+		if ref, is := value.(dbRef); is {
+			id = ref.Id()
+		} else if value != nil {
+			id = rv.Convert(tyI64).Interface().(I64)
+		}
+		field.Addr().Interface().(interface{ SetId(I64) }).SetId(id)
+	case !rv.IsValid():
+		field.Set(reflect.Zero(field.Type()))
+	case rv.Type() == field.Type():
+		field.Set(rv)
+	case rv.CanConvert(field.Type()):
+		field.Set(rv.Convert(field.Type()))
+	default:
+		panic(str.Fmt("in-memory backend: cannot set %s from %T", field.Type(), value))
+	}
+}
+
+func (me *inMemDb) ids(desc *structDesc, query q.Query, orderBy []q.OrderBy) (ret []I64) {
+	c2f := func(col q.C) q.F {
+		if idx := sl.IdxOf(desc.cols, col); idx >= 0 {
+			return desc.fields[idx]
+		}
+		inMemUnsupported("query or order on column '" + string(col) + "'")
+		return ""
+	}
+	for _, o := range orderBy {
+		if o.Expr() != nil {
+			inMemUnsupported("ordering by expressions such as `FullTextRank`")
+		}
+	}
+	query, with_deleted := withDeletedFrom(query)
+	tbl := me.table(desc)
+	for id, row := range tbl.rows {
+		if (with_deleted || !tbl.deleted[id]) && ((query == nil) || (query.Eval(inMemRow{row}, c2f) == nil)) {
+			ret = append(ret, id)
+		}
+	}
+	sort.Slice(ret, func(i int, j int) bool {
+		for _, o := range orderBy {
+			fld := o.Field()
+			if fld == "" {
+				fld = c2f(o.Col())
+			}
+			lhs, rhs := inMemEvalVal(inMemField(tbl.rows[ret[i]], fld).Interface()), inMemEvalVal(inMemField(tbl.rows[ret[j]], fld).Interface())
+			if lhs_null, rhs_null := (lhs == inMemNull{}), (rhs == inMemNull{}); lhs_null || rhs_null { // as in PG: NULLs last when ascending, first when descending
+				if lhs_null != rhs_null {
+					return lhs_null == o.Desc()
+				}
+				continue
+			}
+			if lt := ReflLt(reflect.ValueOf(lhs), reflect.ValueOf(rhs)); lt || ReflLt(reflect.ValueOf(rhs), reflect.ValueOf(lhs)) {
+				return lt != o.Desc()
+			}
+		}
+		return ret[i] < ret[j]
+	})
+	return
+}
+
+func inMemFind[T any](query q.Query, maxResults int, orderBy ...q.OrderBy) (ret []*T) {
+	inMem.Lock()
+	defer inMem.Unlock()
+	desc := desc[T]()
+	tbl := inMem.table(desc)
+	for _, id := range inMem.ids(desc, query, orderBy) {
+		if (maxResults > 0) && (len(ret) == maxResults) {
+			break
+		}
+		rec := inMemCopy(tbl.rows[id]).Interface().(*T)
+		if self_versioning, _ := ((any)(rec)).(SelfVersioningObj); self_versioning != nil {
+			self_versioning.OnAfterLoaded()
+		}
+		ret = append(ret, rec)
+	}
+	return
+}
+
+func inMemCount[T any](query q.Query, nonNullColumn q.C, distinct *q.C) (ret int64) {
+	inMem.Lock()
+	defer inMem.Unlock()
+	desc := desc[T]()
+	tbl, col, seen := inMem.table(desc), nonNullColumn, []any{}
+	if distinct != nil {
+		col = *distinct
+	}
+	for _, id := range inMem.ids(desc, query, nil) {
+		if col == "" {
+			ret++
+		} else if val := inMemVal(inMemField(tbl.rows[id], desc.fieldNameOfCol(col))); (val != nil) &&
+			((distinct == nil) || !sl.Any(seen, func(it any) bool { return reflect.DeepEqual(it, val) })) {
+			seen = append(seen, val)
+			ret++
+		}
+	}
+	return
+}
+
+// check enforces `Unique`s, `Check`s and `Ref` targets existing for `row` (not yet or no longer in `desc`'s table),
+// panicking with the same `Err`s as `dbErrFrom` would.
+func (me *inMemDb) check(desc *structDesc, id I64, row reflect.Value) {
+	tbl := me.table(desc)
+	for _, check := range desc.constraints.checks {
+		if check.cond.Eval(inMemRow{row}, desc.fieldNameOfCol) != nil {
+			panic(check.err)
+		}
+	}
+	for _, field_name := range desc.constraints.uniques {
+		if val := inMemVal(inMemField(row, field_name)); val != nil {
+			for other_id, other := range tbl.rows {
+				if (other_id != id) && reflect.DeepEqual(val, inMemVal(inMemField(other, field_name))) {
+					panic(desc.err(ErrSetDbUnique, string(field_name)))
+				}
+			}
+		}
+	}
+	for _, field_name := range desc.fields {
+		if field := inMemField(row, field_name); isDbRefType(field.Type()) {
+			if ref_id, _ := inMemVal(field).(I64); ref_id != 0 {
+				if ref_desc := refDesc(field.Type()); !me.table(ref_desc).rows[ref_id].IsValid() {
+					panic(desc.err(ErrSetDbRef, string(field_name)))
+				}
+			}
+		}
+	}
+}
+
+func inMemInsert[T any](rec *T) I64 {
+	inMem.Lock()
+	defer inMem.Unlock()
+	return inMem.insert(desc[T](), reflect.ValueOf(rec))
+}
+
+func (me *inMemDb) insert(desc *structDesc, rec reflect.Value) I64 {
+	tbl, row := me.table(desc), inMemCopy(rec)
+	id, now := tbl.lastId+1, DtNow()
+	inMemSet(inMemField(row, desc.fields[0]), id)
+	inMemSet(inMemField(row, desc.fields[1]), now)
+	inMemSet(inMemField(row, desc.fields[2]), now)
+	me.check(desc, id, row)
+	tbl.lastId, tbl.rows[id] = id, row
+	return id
+}
+
+func inMemUpdate[T any](where q.Query, colNames []q.C, colVals []any) (ret int64) {
+	inMem.Lock()
+	defer inMem.Unlock()
+	desc := desc[T]()
+	for _, id := range inMem.ids(desc, where, nil) {
+		inMem.update(desc, id, func(row reflect.Value) {
+			for i, col_name := range colNames {
+				field_name := desc.fieldNameOfCol(col_name)
+				if field := inMemField(row, field_name); field_name == desc.constraints.versioned {
+					inMemSet(field, reflect.ValueOf(inMemVal(field)).Convert(tyI64).Interface().(I64)+1)
+				} else if !sl.Has(desc.constraints.readOnly, field_name) {
+					inMemSet(field, colVals[i])
+				}
+			}
+		})
+		ret++
+	}
+	return
+}
+
+func (me *inMemDb) update(desc *structDesc, id I64, change func(row reflect.Value)) {
+	tbl, row := me.table(desc), reflect.New(desc.ty)
+	row.Elem().Set(tbl.rows[id].Elem())
+	change(row)
+	row = inMemCopy(row) // `change` may have set callers' slices, maps or pointers
+	inMemSet(inMemField(row, desc.fields[2]), DtNow())
+	me.check(desc, id, row)
+	tbl.rows[id] = row
+}
+
+// inMemUpsert inserts `recs`, except those with a `conflictOn` value already in the table, which instead update (and restore if
+// soft-deleted) that row's `overwrite` fields.
+func inMemUpsert[T any](conflictOn []q.F, overwrite []q.F, recs ...*T) {
+	inMem.Lock()
+	defer inMem.Unlock()
+	desc := desc[T]()
+	tbl := inMem.table(desc)
+	for _, rec := range recs {
+		rv, found := reflect.ValueOf(rec), false
+		for id, row := range tbl.rows {
+			if sl.Any(conflictOn, func(it q.F) bool {
+				val := inMemVal(inMemField(rv, it))
+				return (val != nil) && reflect.DeepEqual(val, inMemVal(inMemField(row, it)))
+			}) {
+				found = true
+				delete(tbl.deleted, id)
+				inMem.update(desc, id, func(row reflect.Value) {
+					for _, field_name := range overwrite {
+						if field := inMemField(row, field_name); field_name == desc.constraints.versioned {
+							inMemSet(field, reflect.ValueOf(inMemVal(field)).Convert(tyI64).Interface().(I64)+1)
+						} else {
+							field.Set(inMemField(rv, field_name))
+						}
+					}
+				})
+				break
+			}
+		}
+		if !found {
+			_ = inMem.insert(desc, rv)
+		}
+	}
+}
+
+func inMemDelete[T any](where q.Query) int64 {
+	inMem.Lock()
+	defer inMem.Unlock()
+	desc := desc[T]()
+	tbl, ids := inMem.table(desc), inMem.ids(desc, where, nil)
+	if desc.constraints.softDelete != nil {
+		for _, id := range ids {
+			tbl.deleted[id] = true
+		}
+		return int64(len(ids))
+	}
+	dels, set_nulls := map[*structDesc][]I64{}, []func(){}
+	inMem.deletePlan(desc, ids, dels, &set_nulls) // might panic on `RefOnDelPrevent`, so before any actual changes
+	for _, set_null := range set_nulls {
+		set_null()
+	}
+	for del_desc, del_ids := range dels {
+		for _, id := range del_ids {
+			delete(inMem.table(del_desc).rows, id)
+			delete(inMem.table(del_desc).deleted, id)
+		}
+	}
+	return int64(len(ids))
+}
+
+func (me *inMemDb) deletePlan(desc *structDesc, ids []I64, dels map[*structDesc][]I64, setNulls *[]func()) {
+	ids = sl.Where(ids, func(it I64) bool { return !sl.Has(dels[desc], it) })
+	if len(ids) == 0 {
+		return
+	}
+	dels[desc] = append(dels[desc], ids...)
+	for _, other_desc := range ensureDescs {
+		for _, field_name := range other_desc.fields {
+			field_type := other_desc.fieldTypeOfField(field_name)
+			if (!isDbRefType(field_type)) || (refDesc(field_type) != desc) {
+				continue
+			}
+			on_del := reflect.New(field_type).Interface().(refOnDel).onDelSql()
+			var refd_by []I64
+			for other_id, other_row := range me.table(other_desc).rows {
+				if ref_id, _ := inMemVal(inMemField(other_row, field_name)).(I64); (ref_id != 0) && sl.Has(ids, ref_id) {
+					refd_by = append(refd_by, other_id)
+				}
+			}
+			switch {
+			case len(refd_by) == 0:
+			case on_del == (RefOnDelCascade{}).onDelSql():
+				me.deletePlan(other_desc, refd_by, dels, setNulls)
+			case on_del == (RefOnDelSetNull{}).onDelSql():
+				other_desc, field_name := other_desc, field_name
+				*setNulls = append(*setNulls, func() {
+					for _, other_id := range refd_by {
+						if row := me.table(other_desc).rows[other_id]; row.IsValid() {
+							inMemSet(inMemField(row, field_name), nil)
+						}
+					}
+				})
+			default:
+				panic(other_desc.err(ErrSetDbRef, string(field_name)))
+			}
+		}
+	}
+}
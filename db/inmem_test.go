@@ -0,0 +1,284 @@
+package yodb
+
+import (
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	. "yo/cfg"
+	. "yo/ctx"
+	q "yo/db/query"
+	. "yo/util"
+	"yo/util/sl"
+)
+
+type testInMemParent struct {
+	Id     I64
+	DtMade *DateTime
+	DtMod  *DateTime
+
+	Name  Text
+	Score I64
+	DtDue *DateTime
+	Tags  JsonArr[Text]
+	Attrs JsonMap[I64]
+}
+
+type testInMemChild struct {
+	Id     I64
+	DtMade *DateTime
+	DtMod  *DateTime
+
+	Cascade Ref[testInMemParent, RefOnDelCascade]
+	SetNull Ref[testInMemParent, RefOnDelSetNull]
+	Prevent Ref[testInMemParent, RefOnDelPrevent]
+}
+
+type testInMemTrashable struct {
+	Id     I64
+	DtMade *DateTime
+	DtMod  *DateTime
+
+	Name  Text
+	Score I64
+}
+
+type testInMemVersioned struct {
+	Id     I64
+	DtMade *DateTime
+	DtMod  *DateTime
+
+	Name Text
+	Ver  I64
+}
+
+func TestMain(m *testing.M) {
+	Ensure[testInMemParent, q.F]("", nil, false, Unique[q.F]{"Name"})
+	Ensure[testInMemChild, q.F]("", nil, false)
+	Ensure[testInMemTrashable, q.F]("", nil, false, SoftDelete{}, Unique[q.F]{"Name"})
+	Ensure[testInMemVersioned, q.F]("", nil, false, Versioned[q.F]{"Ver"})
+	Ensure[testFullTextThing, q.F]("", nil, false, FullText[q.F]{"Title"})
+	Ensure[testStmtTrashRef, q.F]("", nil, false)
+	Cfg.YO_DB_PAGE_TOK_SIGN_KEY = "yo_test"
+	os.Exit(m.Run())
+}
+
+func testInMemPanic(t *testing.T, expectErr Err, do func()) {
+	var fail any
+	Try(do, func(err any) { fail = err })
+	if fail != expectErr {
+		t.Errorf("expected %q, got %v", expectErr, fail)
+	}
+}
+
+func testInMemParents(ctx *Ctx, names ...Text) (ret []I64) {
+	for _, name := range names {
+		ret = append(ret, CreateOne(ctx, &testInMemParent{Name: name}))
+	}
+	return
+}
+
+func testInMemChildOf(parentId I64, refField q.F) *testInMemChild {
+	child := &testInMemChild{}
+	switch refField {
+	case "Cascade":
+		child.Cascade.SetId(parentId)
+	case "SetNull":
+		child.SetNull.SetId(parentId)
+	case "Prevent":
+		child.Prevent.SetId(parentId)
+	}
+	return child
+}
+
+func TestInMem(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		do   func(t *testing.T, ctx *Ctx)
+	}{
+		{"Unique on create", func(t *testing.T, ctx *Ctx) {
+			_ = testInMemParents(ctx, "foo")
+			testInMemPanic(t, "DbUnique_testInMemParent_Name", func() { _ = testInMemParents(ctx, "foo") })
+			if n := Count[testInMemParent](ctx, nil, "", nil); n != 1 {
+				t.Errorf("expected 1 parent, got %d", n)
+			}
+		}},
+		{"Unique on update", func(t *testing.T, ctx *Ctx) {
+			ids := testInMemParents(ctx, "foo", "bar")
+			testInMemPanic(t, "DbUnique_testInMemParent_Name", func() {
+				_ = Update(ctx, &testInMemParent{Id: ids[1], Name: "foo"}, nil, false, "Name")
+			})
+			if bar := ById[testInMemParent](ctx, ids[1]); bar.Name != "bar" {
+				t.Errorf("expected unchanged 'bar', got '%s'", bar.Name)
+			}
+		}},
+		{"Ref to non-existing", func(t *testing.T, ctx *Ctx) {
+			testInMemPanic(t, "DbRef_testInMemChild_Cascade", func() { _ = CreateOne(ctx, testInMemChildOf(123, "Cascade")) })
+		}},
+		{"Ref on-delete cascade", func(t *testing.T, ctx *Ctx) {
+			ids := testInMemParents(ctx, "foo", "bar")
+			CreateMany(ctx, testInMemChildOf(ids[0], "Cascade"), testInMemChildOf(ids[0], "Cascade"), testInMemChildOf(ids[1], "Cascade"))
+			if n := Delete[testInMemParent](ctx, ColID.Equal(ids[0])); n != 1 {
+				t.Errorf("expected 1 deleted, got %d", n)
+			}
+			if n := Count[testInMemChild](ctx, nil, "", nil); n != 1 {
+				t.Errorf("expected 1 child left, got %d", n)
+			}
+		}},
+		{"Ref on-delete set-null", func(t *testing.T, ctx *Ctx) {
+			ids := testInMemParents(ctx, "foo")
+			child_id := CreateOne(ctx, testInMemChildOf(ids[0], "SetNull"))
+			_ = Delete[testInMemParent](ctx, ColID.Equal(ids[0]))
+			if child := ById[testInMemChild](ctx, child_id); (child == nil) || (child.SetNull.Id() != 0) {
+				t.Errorf("expected child with nulled ref, got %#v", child)
+			}
+			if n := Count[testInMemChild](ctx, q.F("SetNull").Equal(nil), "", nil); n != 1 {
+				t.Errorf("expected nulled ref to equal nil, got %d", n)
+			}
+		}},
+		{"Ref on-delete prevent", func(t *testing.T, ctx *Ctx) {
+			ids := testInMemParents(ctx, "foo", "bar")
+			_ = CreateOne(ctx, testInMemChildOf(ids[0], "Prevent"))
+			_ = CreateOne(ctx, testInMemChildOf(ids[1], "Cascade"))
+			testInMemPanic(t, "DbRef_testInMemChild_Prevent", func() { _ = Delete[testInMemParent](ctx, q.F("Name").In("foo", "bar")) })
+			if n := Count[testInMemParent](ctx, nil, "", nil) + Count[testInMemChild](ctx, nil, "", nil); n != 4 {
+				t.Errorf("expected nothing deleted, got %d rows left", n)
+			}
+		}},
+		{"Update", func(t *testing.T, ctx *Ctx) {
+			ids := testInMemParents(ctx, "foo", "bar", "baz")
+			if n := Update(ctx, &testInMemParent{Score: 22}, q.F("Name").NotEqual("foo"), false, "Score"); n != 2 {
+				t.Errorf("expected 2 updated, got %d", n)
+			}
+			scores := sl.As(FindMany[testInMemParent](ctx, nil, 0, nil, FieldID.Asc()), func(it *testInMemParent) I64 { return it.Score })
+			if !slices.Equal(scores, []I64{0, 22, 22}) {
+				t.Errorf("expected scores 0,22,22, got %v", scores)
+			}
+			if n := Update(ctx, &testInMemParent{Id: ids[0], Score: 11}, nil, true); n != 1 {
+				t.Errorf("expected 1 updated, got %d", n)
+			}
+			if foo := ById[testInMemParent](ctx, ids[0]); (foo.Score != 11) || (foo.Name != "foo") {
+				t.Errorf("expected foo with 11, got %s with %d", foo.Name, foo.Score)
+			}
+		}},
+		{"Rows not aliased", func(t *testing.T, ctx *Ctx) {
+			rec := &testInMemParent{Name: "foo", Tags: JsonArr[Text]{"a", "b"}, Attrs: JsonMap[I64]{"x": 1}}
+			id := CreateOne(ctx, rec)
+			rec.Tags[0], rec.Attrs["x"] = "changed", 2
+			foo := ById[testInMemParent](ctx, id)
+			if (foo.Tags[0] != "a") || (foo.Attrs["x"] != 1) {
+				t.Errorf("expected stored row unaffected by changes to the created rec, got %v and %v", foo.Tags, foo.Attrs)
+			}
+			foo.Tags[1], foo.Attrs["y"] = "changed", 3
+			if foo = ById[testInMemParent](ctx, id); (foo.Tags[1] != "b") || (len(foo.Attrs) != 1) {
+				t.Errorf("expected stored row unaffected by changes to a loaded rec, got %v and %v", foo.Tags, foo.Attrs)
+			}
+			upd := &testInMemParent{Id: id, Name: "foo", Tags: JsonArr[Text]{"c"}}
+			_ = Update(ctx, upd, nil, false, "Tags")
+			upd.Tags[0] = "changed"
+			if foo = ById[testInMemParent](ctx, id); foo.Tags[0] != "c" {
+				t.Errorf("expected stored row unaffected by changes to the updating rec, got %v", foo.Tags)
+			}
+		}},
+		{"Upsert", func(t *testing.T, ctx *Ctx) {
+			ids := testInMemParents(ctx, "foo")
+			Upsert(ctx, &testInMemParent{Name: "foo", Score: 11})
+			Upsert(ctx, &testInMemParent{Name: "bar", Score: 22})
+			if foo := ById[testInMemParent](ctx, ids[0]); foo.Score != 11 {
+				t.Errorf("expected foo upserted to 11, got %d", foo.Score)
+			}
+			if bar := FindOne[testInMemParent](ctx, q.F("Name").Equal("bar")); (bar == nil) || (bar.Score != 22) {
+				t.Errorf("expected bar inserted with 22, got %#v", bar)
+			}
+		}},
+		{"UpsertMany", func(t *testing.T, ctx *Ctx) {
+			_ = testInMemParents(ctx, "foo")
+			UpsertMany(ctx, "Name", []q.F{"Score"}, &testInMemParent{Name: "foo", Score: 11}, &testInMemParent{Name: "bar", Score: 22})
+			if n := Count[testInMemParent](ctx, q.F("Score").GreaterThan(10), "", nil); n != 2 {
+				t.Errorf("expected 2 with score > 10, got %d", n)
+			}
+		}},
+		{"UpsertMany with dupes", func(t *testing.T, ctx *Ctx) {
+			UpsertMany(ctx, "Name", []q.F{"Score"}, &testInMemParent{Name: "foo", Score: 11}, &testInMemParent{Name: "bar", Score: 22}, &testInMemParent{Name: "foo", Score: 33})
+			scores := sl.As(FindMany[testInMemParent](ctx, nil, 0, nil, q.F("Name").Asc()), func(it *testInMemParent) I64 { return it.Score })
+			if !slices.Equal(scores, []I64{22, 33}) {
+				t.Errorf("expected scores 22,33, got %v", scores)
+			}
+		}},
+		{"UpsertMany restoring soft-deleted", func(t *testing.T, ctx *Ctx) {
+			CreateMany(ctx, &testInMemTrashable{Name: "foo"}, &testInMemTrashable{Name: "bar"})
+			_ = Delete[testInMemTrashable](ctx, q.F("Name").Equal("foo"))
+			UpsertMany(ctx, "Name", []q.F{"Score"}, &testInMemTrashable{Name: "foo", Score: 11})
+			if foo := FindOne[testInMemTrashable](ctx, q.F("Name").Equal("foo")); (foo == nil) || (foo.Score != 11) {
+				t.Errorf("expected foo restored with 11, got %#v", foo)
+			}
+		}},
+		{"Delete", func(t *testing.T, ctx *Ctx) {
+			_ = testInMemParents(ctx, "foo", "bar", "baz")
+			if n := Delete[testInMemParent](ctx, q.F("Name").In("bar", "baz", "nope")); n != 2 {
+				t.Errorf("expected 2 deleted, got %d", n)
+			}
+			if names := sl.As(FindMany[testInMemParent](ctx, nil, 0, nil), func(it *testInMemParent) Text { return it.Name }); !slices.Equal(names, []Text{"foo"}) {
+				t.Errorf("expected only foo left, got %v", names)
+			}
+		}},
+		{"SoftDelete", func(t *testing.T, ctx *Ctx) {
+			CreateMany(ctx, &testInMemTrashable{Name: "foo"}, &testInMemTrashable{Name: "bar"}, &testInMemTrashable{Name: "baz"})
+			if n := Delete[testInMemTrashable](ctx, q.F("Name").NotEqual("foo")); n != 2 {
+				t.Errorf("expected 2 deleted, got %d", n)
+			}
+			if n := Count[testInMemTrashable](ctx, nil, "", nil); n != 1 {
+				t.Errorf("expected 1 left, got %d", n)
+			}
+			if n := Count[testInMemTrashable](ctx, WithDeleted[testInMemTrashable](nil), "", nil); n != 3 {
+				t.Errorf("expected 3 with deleted, got %d", n)
+			}
+			if n := Count[testInMemTrashable](ctx, WithDeleted[testInMemTrashable](q.F("Name").Equal("bar")).Or(q.F("Name").Equal("baz")), "", nil); n != 2 {
+				t.Errorf("expected 2 with deleted, got %d", n)
+			}
+		}},
+		{"Versioned", func(t *testing.T, ctx *Ctx) {
+			id := CreateOne(ctx, &testInMemVersioned{Name: "foo"})
+			fresh, stale := &testInMemVersioned{Id: id, Name: "bar"}, &testInMemVersioned{Id: id, Name: "baz"}
+			if n := Update(ctx, fresh, nil, false, "Name"); (n != 1) || (fresh.Ver != 1) {
+				t.Errorf("expected 1 updated to version 1, got %d to version %d", n, fresh.Ver)
+			}
+			testInMemPanic(t, ErrDbUpdate_Conflict, func() { _ = Update(ctx, stale, nil, false, "Name") })
+			testInMemPanic(t, ErrDbUpdate_ExpectedNoQueryForVersionedUpdate, func() {
+				_ = Update(ctx, &testInMemVersioned{Name: "baz"}, ColID.Equal(id), false, "Name")
+			})
+			if obj := ById[testInMemVersioned](ctx, id); (obj.Name != "bar") || (obj.Ver != 1) {
+				t.Errorf("expected 'bar' at version 1, got '%s' at version %d", obj.Name, obj.Ver)
+			}
+		}},
+		{"Paged", func(t *testing.T, ctx *Ctx) {
+			now := time.Now()
+			ids := testInMemParents(ctx, "a", "b", "c", "d", "e")
+			for i, days := range []int{2, -1, 1, -1, 2} { // -1 for NULL
+				if days >= 0 {
+					_ = Update(ctx, &testInMemParent{Id: ids[i], DtDue: DtFrom(now.AddDate(0, 0, days))}, nil, false, "DtDue")
+				}
+			}
+			var names []Text
+			for page_tok, num_pages := "", 0; (num_pages == 0) || (page_tok != ""); num_pages++ {
+				var page []*testInMemParent
+				page, page_tok = Paged[testInMemParent](ctx, nil, 2, page_tok, q.F("DtDue").Asc())
+				names = append(names, sl.As(page, func(it *testInMemParent) Text { return it.Name })...)
+				if num_pages > 3 {
+					t.Fatal("too many pages")
+				}
+			}
+			if !slices.Equal(names, []Text{"c", "a", "e", "b", "d"}) {
+				t.Errorf("expected c,a,e,b,d, got %v", names)
+			}
+		}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			_ = InitInMem()
+			ctx := NewCtxNonHttp(time.Minute, false, "")
+			defer ctx.OnDone(nil)
+			test.do(t, ctx)
+		})
+	}
+}
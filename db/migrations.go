@@ -0,0 +1,210 @@
+package yodb
+
+import (
+	"sort"
+	"time"
+
+	. "yo/cfg"
+	. "yo/ctx"
+	q "yo/db/query"
+	yolog "yo/log"
+	. "yo/util"
+	"yo/util/sl"
+	"yo/util/str"
+)
+
+const migsTableName = "yo_db_migs_"
+
+// Migrations are numbered steps passed to `Ensure` among its `constraints`. Each step not yet recorded
+// in the migrations-history table for this struct's table runs once (in ascending `Version` order),
+// after any columns / table renames were added but before any columns are dropped, all in the same TX.
+// For newly-created tables, all steps are recorded as done without being run.
+type Migrations []Migration
+
+type Migration struct {
+	Version int
+	Sql     string     // either this
+	Go      func(*Ctx) // or this, but not both
+}
+
+// AckDestructive lists the columns whose dropping or type-narrowing (from `Ensure` changes) is deliberate.
+// Without these acknowledgements, such changes refuse to boot the app.
+type AckDestructive []q.C
+
+// ColConvs supply per-row conversions for column type changes that aren't safe widenings (those happen automatically).
+// The old column is renamed (with a `convsrc_` suffix) and a new-typed one added in its place, then every row converted,
+// and the old column is dropped only after that and any pending `Migrations`. For `Unique` columns, the uniqueness
+// constraint is only re-added then, too. Indices and `Check`s on the column get re-created as for all alterations.
+type ColConvs []ColConv
+
+type ColConv struct {
+	Col       q.C
+	BatchSize int // defaults to 1000
+	// Conv receives the old column value as scanned into an `any` by `database/sql` and returns the new value
+	Conv func(ctx *Ctx, id I64, oldValue any) (newValue any)
+}
+
+func (Migrations) qFs() []q.F     { return nil }
+func (AckDestructive) qFs() []q.F { return nil }
+func (ColConvs) qFs() []q.F       { return nil }
+
+func (me *ColConv) srcCol() q.C { return me.Col + "convsrc_" }
+
+// run converts all rows in batches of `BatchSize`, each batch selected by ascending `id_` (inside `ctx`'s TX).
+func (me *ColConv) run(ctx *Ctx, desc *structDesc) {
+	if Cfg.YO_DB_MIG_DRY_RUN {
+		yolog.Println("-- %s: would convert all rows from %s into %s", desc.tableName, me.srcCol(), me.Col)
+		return
+	}
+	batch_size, last_id := If(me.BatchSize > 0, me.BatchSize, 1000), I64(0)
+	for {
+		ctx.Timings.Step("convert " + string(me.Col) + " after id " + str.FromI64(int64(last_id), 10))
+		stmt := new(sqlStmt)
+		(*str.Buf)(stmt).WriteString("SELECT " + string(ColID) + ", " + string(me.srcCol()) + " FROM " + desc.tableName +
+			" WHERE " + string(ColID) + " > @L ORDER BY " + string(ColID) + " LIMIT " + str.FromInt(batch_size))
+		var ids []I64
+		var olds []any
+		func() {
+			args, time_started := dbArgs{"L": last_id}, time.Now()
+			printIfDbgMode(ctx, stmt.String(), args)
+			defer func() { dbStatsRecord(ctx, stmt.String(), args, time_started, 0, int64(len(ids)), true) }() // also if failed
+			rows, err := ctx.Db.Tx.QueryContext(ctx, stmt.String(), args)
+			if rows != nil {
+				defer rows.Close()
+			}
+			if err != nil {
+				panic(err)
+			}
+			for rows.Next() {
+				var id int64
+				var old any
+				if err = rows.Scan(&id, &old); err != nil {
+					panic(err)
+				}
+				ids, olds = append(ids, I64(id)), append(olds, old)
+			}
+			if err = rows.Err(); err != nil {
+				panic(err)
+			}
+		}()
+		for i, id := range ids {
+			stmt := new(sqlStmt)
+			(*str.Buf)(stmt).WriteString("UPDATE " + desc.tableName + " SET " + string(me.Col) + " = @V WHERE " + string(ColID) + " = @I")
+			_ = doExec(ctx, stmt, dbArgs{"V": me.Conv(ctx, id, olds[i]), "I": id})
+		}
+		if len(ids) < batch_size {
+			break
+		}
+		last_id = ids[len(ids)-1]
+	}
+}
+
+type dbMig struct {
+	TableName Text
+	Version   I64
+	DtDone    *DateTime
+}
+
+func (me Migrations) ensureValid(tableName string) {
+	for i, mig := range me {
+		if mig.Version <= 0 {
+			panic(tableName + ": migration version must be > 0")
+		} else if (mig.Sql == "") == (mig.Go == nil) {
+			panic(tableName + ": migration " + str.FromInt(mig.Version) + " must have either Sql or Go")
+		} else if sl.IdxWhere(me, func(it Migration) bool { return it.Version == mig.Version }) != i {
+			panic(tableName + ": duplicate migration version " + str.FromInt(mig.Version))
+		}
+	}
+}
+
+func (me ColConvs) ensureValid(desc *structDesc) {
+	for i, conv := range me {
+		if conv.Conv == nil {
+			panic(desc.tableName + ": no Conv for column conversion '" + string(conv.Col) + "'")
+		} else if !sl.Has(desc.cols, conv.Col) {
+			panic(desc.tableName + ": column conversion for unknown column '" + string(conv.Col) + "'")
+		} else if sl.IdxWhere(me, func(it ColConv) bool { return it.Col == conv.Col }) != i {
+			panic(desc.tableName + ": duplicate column conversion '" + string(conv.Col) + "'")
+		}
+	}
+}
+
+func (me Migrations) sorted() Migrations {
+	ret := append(Migrations{}, me...)
+	sort.SliceStable(ret, func(i int, j int) bool { return ret[i].Version < ret[j].Version })
+	return ret
+}
+
+func migsEnsureTable(ctx *Ctx) {
+	if GetTable(ctx, migsTableName) != nil {
+		return
+	}
+	stmt := new(sqlStmt)
+	w := (*str.Buf)(stmt).WriteString
+	w("CREATE TABLE IF NOT EXISTS ")
+	w(migsTableName)
+	w(" (\n\ttable_name_ text NOT NULL,\n\tversion_ int8 NOT NULL,\n\tdt_done_ timestamp without time zone NOT NULL DEFAULT (current_timestamp),\n\tPRIMARY KEY (table_name_, version_)\n)")
+	migsExecOrPrint(ctx, stmt, nil)
+}
+
+func migsDone(ctx *Ctx, tableName string) (ret []I64) {
+	if Cfg.YO_DB_MIG_DRY_RUN && (GetTable(ctx, migsTableName) == nil) {
+		return nil
+	}
+	desc := desc[dbMig]()
+	desc.tableName = migsTableName
+
+	args := dbArgs{}
+	stmt := new(sqlStmt).selCols(desc, nil, true).
+		fromAndJoinAndWhereAndOrderBy(desc, false, q.C("table_name_").Equal(tableName), args, q.C("version_").Asc())
+	for _, it := range doSelect[dbMig](ctx, true, stmt, args, 0) {
+		ret = append(ret, it.Version)
+	}
+	return
+}
+
+func migsRecordDone(ctx *Ctx, tableName string, version int) {
+	stmt := new(sqlStmt)
+	(*str.Buf)(stmt).WriteString("INSERT INTO " + migsTableName + " (table_name_, version_) VALUES (@T, @V)")
+	migsExecOrPrint(ctx, stmt, dbArgs{"T": tableName, "V": version})
+}
+
+// migsRecordRename moves the migrations history of a table renamed via `Ensure`'s `oldTableName` along with it, so that no steps re-run.
+func migsRecordRename(ctx *Ctx, oldTableName string, newTableName string) {
+	stmt := new(sqlStmt)
+	(*str.Buf)(stmt).WriteString("UPDATE " + migsTableName + " SET table_name_ = @New WHERE table_name_ = @Old")
+	migsExecOrPrint(ctx, stmt, dbArgs{"New": newTableName, "Old": oldTableName})
+}
+
+// migsRun runs (or, if `isNewTable`, merely records) all `desc.mig.steps` not yet in the migrations-history table.
+func migsRun(ctx *Ctx, desc *structDesc, isNewTable bool) (didRun bool) {
+	done := migsDone(ctx, desc.tableName)
+	for _, mig := range desc.mig.steps.sorted() {
+		if sl.Has(done, I64(mig.Version)) {
+			continue
+		}
+		if !isNewTable {
+			didRun = true
+			ctx.Timings.Step("migration " + str.FromInt(mig.Version))
+			if mig.Sql != "" {
+				stmt := new(sqlStmt)
+				(*str.Buf)(stmt).WriteString(mig.Sql)
+				migsExecOrPrint(ctx, stmt, nil)
+			} else if Cfg.YO_DB_MIG_DRY_RUN {
+				yolog.Println("-- %s: would run Go migration %d", desc.tableName, mig.Version)
+			} else {
+				mig.Go(ctx)
+			}
+		}
+		migsRecordDone(ctx, desc.tableName, mig.Version)
+	}
+	return
+}
+
+func migsExecOrPrint(ctx *Ctx, stmt *sqlStmt, args dbArgs) {
+	if !Cfg.YO_DB_MIG_DRY_RUN {
+		_ = doExec(ctx, stmt, args)
+	} else {
+		yolog.Println(str.TrimSuff(stmt.String(), ",") + If(len(args) == 0, ";", ";  -- "+str.GoLike(args)))
+	}
+}
@@ -0,0 +1,153 @@
+package yodb
+
+import (
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	. "yo/ctx"
+	q "yo/db/query"
+	"yo/util/str"
+)
+
+type testColConvThing struct {
+	Id     I64
+	DtMade *DateTime
+	DtMod  *DateTime
+
+	Amount I64
+	Code   Text
+}
+
+func testColConvDesc(tableName string) *structDesc {
+	desc := desc[testColConvThing]()
+	desc.tableName, desc.constraints.uniques = tableName, []q.F{"Code"}
+	desc.mig.colConvs = ColConvs{
+		{Col: "amount_", BatchSize: 2, Conv: func(ctx *Ctx, id I64, oldValue any) any {
+			ret, _ := strconv.ParseInt(oldValue.(string), 10, 64)
+			return ret
+		}},
+		{Col: "code_", Conv: func(ctx *Ctx, id I64, oldValue any) any { return "c" + str.FromI64(oldValue.(int64), 10) }},
+	}
+	return desc
+}
+
+func TestColConvStmts(t *testing.T) {
+	desc := testColConvDesc("yo_test_colconv_stmts_")
+	cur_table := []*TableColumn{{ColumnName: "id_", DataType: "bigint"}, {ColumnName: "dt_made_", DataType: "timestamp without time zone"},
+		{ColumnName: "dt_mod_", DataType: "timestamp without time zone"}, {ColumnName: "amount_", DataType: "text"}, {ColumnName: "code_", DataType: "bigint"}}
+	stmts, convs, stmts_after_migs := schemaAlterTable(desc, cur_table)
+	if len(convs) != 2 {
+		t.Fatalf("expected 2 convs, got %d", len(convs))
+	}
+	for _, expect := range []struct {
+		stmts []*sqlStmt
+		sql   string
+	}{
+		{stmts, "ALTER TABLE yo_test_colconv_stmts_ RENAME COLUMN amount_ TO amount_convsrc_"},
+		{stmts, "ALTER TABLE yo_test_colconv_stmts_ \n\tADD COLUMN amount_ int8 NOT NULL DEFAULT (0)"},
+		{stmts, "ALTER TABLE yo_test_colconv_stmts_ RENAME COLUMN code_ TO code_convsrc_"},
+		{stmts, "ALTER TABLE yo_test_colconv_stmts_ \n\tADD COLUMN code_ text NOT NULL DEFAULT ('')"},
+		{stmts_after_migs, "ALTER TABLE yo_test_colconv_stmts_ \n\tDROP COLUMN amount_convsrc_"},
+		{stmts_after_migs, "ALTER TABLE yo_test_colconv_stmts_ \n\tDROP COLUMN code_convsrc_, \n\tADD UNIQUE (code_)"},
+	} {
+		found := false
+		for _, stmt := range expect.stmts {
+			found = found || (stmt.String() == expect.sql)
+		}
+		if !found {
+			t.Errorf("missing: %s", expect.sql)
+		}
+	}
+}
+
+// TestColConvRun needs a disposable Postgres DB in `YO_DB_CONN_URL`, else it's skipped.
+func TestColConvRun(t *testing.T) {
+	conn_url := os.Getenv("YO_DB_CONN_URL")
+	if conn_url == "" {
+		t.Skip("no YO_DB_CONN_URL")
+	}
+	if DB == nil {
+		DB = dbOpen(conn_url)
+	}
+	const table_name = "yo_test_colconv_"
+	ctx := NewCtxNonHttp(time.Minute, false, "")
+	defer ctx.OnDone(nil)
+	exec := func(sql string) {
+		stmt := new(sqlStmt)
+		(*str.Buf)(stmt).WriteString(sql)
+		_ = doExec(ctx, stmt, nil)
+	}
+	exec("DROP TABLE IF EXISTS " + table_name)
+	defer exec("DROP TABLE IF EXISTS " + table_name)
+	exec("CREATE TABLE " + table_name + " (id_ bigserial PRIMARY KEY, dt_made_ timestamp without time zone NOT NULL DEFAULT (now()), " +
+		"dt_mod_ timestamp without time zone NOT NULL DEFAULT (now()), amount_ text NOT NULL DEFAULT (''), code_ int8 NOT NULL DEFAULT (0))")
+	exec("INSERT INTO " + table_name + " (amount_, code_) VALUES ('11', 1), ('22', 2), ('33', 3), ('44', 4), ('55', 5)")
+
+	desc := testColConvDesc(table_name)
+	stmts, convs, stmts_after_migs := schemaAlterTable(desc, GetTable(ctx, table_name))
+	for _, stmt := range stmts {
+		migsExecOrPrint(ctx, stmt, nil)
+	}
+	for i := range convs {
+		convs[i].run(ctx, desc)
+	}
+	for _, stmt := range stmts_after_migs {
+		migsExecOrPrint(ctx, stmt, nil)
+	}
+
+	if cols := GetTable(ctx, table_name); len(cols) != 5 {
+		t.Fatalf("expected 5 columns after conversion, got %d", len(cols))
+	}
+	args := dbArgs{}
+	rows := doSelect[testColConvThing](ctx, true, new(sqlStmt).selCols(desc, nil, true).fromAndJoinAndWhereAndOrderBy(desc, false, nil, args, ColID.Asc()), args, 0)
+	if len(rows) != 5 {
+		t.Fatalf("expected 5 rows, got %d", len(rows))
+	}
+	for i, row := range rows {
+		if expect_amount, expect_code := I64(11*(i+1)), Text("c"+str.FromInt(i+1)); (row.Amount != expect_amount) || (row.Code != expect_code) {
+			t.Errorf("row %d: expected %d/%s, got %d/%s", row.Id, expect_amount, expect_code, row.Amount, row.Code)
+		}
+	}
+}
+
+// TestMigsRunAfterRename needs a disposable Postgres DB in `YO_DB_CONN_URL`, else it's skipped.
+func TestMigsRunAfterRename(t *testing.T) {
+	conn_url := os.Getenv("YO_DB_CONN_URL")
+	if conn_url == "" {
+		t.Skip("no YO_DB_CONN_URL")
+	}
+	if DB == nil {
+		DB = dbOpen(conn_url)
+	}
+	const old_table_name, new_table_name = "yo_test_migs_old_", "yo_test_migs_new_"
+	ctx := NewCtxNonHttp(time.Minute, false, "")
+	defer ctx.OnDone(nil)
+	exec := func(sql string) {
+		stmt := new(sqlStmt)
+		(*str.Buf)(stmt).WriteString(sql)
+		_ = doExec(ctx, stmt, nil)
+	}
+	migsEnsureTable(ctx)
+	exec("DELETE FROM " + migsTableName + " WHERE table_name_ IN ('" + old_table_name + "', '" + new_table_name + "')")
+	defer exec("DELETE FROM " + migsTableName + " WHERE table_name_ IN ('" + old_table_name + "', '" + new_table_name + "')")
+
+	var num_runs [3]int
+	desc := *desc[testColConvThing]()
+	desc.tableName, desc.mig.oldTableName = new_table_name, old_table_name
+	desc.mig.steps = Migrations{
+		{Version: 1, Go: func(*Ctx) { num_runs[1]++ }},
+		{Version: 2, Go: func(*Ctx) { num_runs[2]++ }},
+	}
+	migsRecordDone(ctx, old_table_name, 1) // as if run before the rename
+
+	migsRecordRename(ctx, old_table_name, new_table_name)
+	_ = migsRun(ctx, &desc, false)
+	if num_runs != [3]int{0, 0, 1} {
+		t.Errorf("expected only step 2 to run once, got %v", num_runs)
+	}
+	if done := migsDone(ctx, new_table_name); len(done) != 2 {
+		t.Errorf("expected 2 steps recorded for %s, got %v", new_table_name, done)
+	}
+}
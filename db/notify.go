@@ -0,0 +1,211 @@
+package yodb
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	. "yo/ctx"
+	q "yo/db/query"
+	yojson "yo/json"
+	yolog "yo/log"
+	. "yo/util"
+	"yo/util/sl"
+	"yo/util/str"
+
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+const notifyChannel = "yo_db_changes"
+
+// Notify makes every insert, update and delete on the `Ensure`d table send a Postgres NOTIFY (via a DB trigger),
+// dispatched to the in-process `Subscribe`rs, whichever process (or DB client) did the write.
+type Notify struct{}
+
+func (Notify) qFs() []q.F { return nil }
+
+type ChangeOp string
+
+const (
+	ChangeInsert ChangeOp = "INSERT"
+	ChangeUpdate ChangeOp = "UPDATE"
+	ChangeDelete ChangeOp = "DELETE"
+)
+
+type Change[T any] struct {
+	Op  ChangeOp
+	Id  I64
+	Obj *T // the object as (re)loaded on notification, `nil` for `ChangeDelete`s (or if already deleted since)
+}
+
+type changeSub struct {
+	ops      []ChangeOp
+	filter   q.Query
+	onChange func(op ChangeOp, id I64, obj any)
+}
+
+var notifySubs = struct {
+	sync.Mutex
+	listening bool
+	byTable   map[string][]*changeSub
+	loaders   map[string]func(*Ctx, I64) any
+}{byTable: map[string][]*changeSub{}, loaders: map[string]func(*Ctx, I64) any{}}
+
+// Subscribe has `onChange` called for every one of `ops` (all if empty) on the `Notify` table of `T` (once `Init`ed and
+// thereafter), as long as `filter` (which may be `nil`) is true for the changed object, which is not checked for `ChangeDelete`s.
+// Changed objects are only (re)loaded for dispatch if any subscriber of their table is interested in the `ChangeOp` at hand.
+// All `onChange`s are called one after another from a single background goroutine, so should return quickly.
+// Deliveries are not guaranteed: changes during DB reconnects are missed. With `InitInMem`, no changes are ever delivered.
+func Subscribe[T any](ops []ChangeOp, filter q.Query, onChange func(*Change[T])) (unsubscribe func()) {
+	desc := desc[T]()
+	if desc.constraints.notify == nil {
+		panic("Subscribe on non-Notify " + desc.tableName)
+	}
+	sub := &changeSub{ops: ops, filter: filter, onChange: func(op ChangeOp, id I64, obj any) {
+		obj_t, _ := obj.(*T)
+		onChange(&Change[T]{Op: op, Id: id, Obj: obj_t})
+	}}
+	notifySubs.Lock()
+	defer notifySubs.Unlock()
+	notifySubs.byTable[desc.tableName] = append(notifySubs.byTable[desc.tableName], sub)
+	notifySubs.loaders[desc.tableName] = func(ctx *Ctx, id I64) any {
+		if obj := ById[T](ctx, id); obj != nil {
+			return obj
+		}
+		return nil
+	}
+	if inited && (DB != nil) && !notifySubs.listening {
+		notifySubs.listening = true
+		go notifyListen()
+	}
+	return func() {
+		notifySubs.Lock()
+		defer notifySubs.Unlock()
+		subs := notifySubs.byTable[desc.tableName]
+		for i, it := range subs {
+			if it == sub {
+				notifySubs.byTable[desc.tableName] = append(subs[:i:i], subs[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+func notifyListenIfSubs() {
+	notifySubs.Lock()
+	defer notifySubs.Unlock()
+	if (len(notifySubs.byTable) > 0) && !notifySubs.listening {
+		notifySubs.listening = true
+		go notifyListen()
+	}
+}
+
+func notifyListen() {
+	for {
+		Try(notifyListenConn, func(err any) {
+			yolog.Println("db: LISTEN %s: %v", notifyChannel, err)
+		})
+		time.Sleep(time.Second)
+	}
+}
+
+func notifyListenConn() {
+	ctx := context.Background()
+	conn, err := DB.Conn(ctx)
+	if err != nil {
+		panic(err)
+	}
+	defer conn.Close()
+	if _, err = conn.ExecContext(ctx, "LISTEN "+notifyChannel); err != nil {
+		panic(err)
+	}
+	if err = conn.Raw(func(driverConn any) error {
+		pg_conn := driverConn.(*stdlib.Conn).Conn()
+		for {
+			notification, err := pg_conn.WaitForNotification(ctx)
+			if err != nil {
+				return err
+			}
+			notifyDispatch(notification.Payload)
+		}
+	}); (err != nil) && (err != sql.ErrConnDone) {
+		panic(err)
+	}
+}
+
+func notifyDispatch(payload string) {
+	var change struct {
+		Table string   `json:"t"`
+		Op    ChangeOp `json:"op"`
+		Id    I64      `json:"id"`
+	}
+	yojson.Load([]byte(payload), &change)
+
+	notifySubs.Lock()
+	subs := sl.Where(notifySubs.byTable[change.Table], func(it *changeSub) bool { return (len(it.ops) == 0) || sl.Has(it.ops, change.Op) })
+	load := notifySubs.loaders[change.Table]
+	notifySubs.Unlock()
+	if len(subs) == 0 {
+		return
+	}
+	var obj any
+	if change.Op != ChangeDelete {
+		ctx := NewCtxNonHttp(time.Minute, false, "")
+		ctx.Db.ReadYourWrites = true // replicas might not have it yet
+		Try(func() { obj = load(ctx, change.Id) }, func(err any) {
+			yolog.Println("db: NOTIFY %s: %v", str.GoLike(change), err)
+		})
+		ctx.OnDone(nil)
+	}
+	desc := ensureDescs[sl.IdxWhere(ensureDescs, func(it *structDesc) bool { return it.tableName == change.Table })]
+	for _, sub := range subs {
+		if (sub.filter != nil) && (change.Op != ChangeDelete) &&
+			((obj == nil) || (sub.filter.Eval(obj, desc.fieldNameOfCol) != nil)) {
+			continue
+		}
+		Try(func() { sub.onChange(change.Op, change.Id, obj) }, func(err any) {
+			yolog.Println("db: NOTIFY %s: %v", str.GoLike(change), err)
+		})
+	}
+}
+
+func schemaNotifyStmts(desc *structDesc) (ret []*sqlStmt) {
+	for _, sql_raw := range []string{
+		`CREATE OR REPLACE FUNCTION on_yo_db_obj_notify()
+			RETURNS TRIGGER
+			LANGUAGE plpgsql AS
+			$func$
+			BEGIN
+				PERFORM pg_notify('` + notifyChannel + `', json_build_object('t', TG_TABLE_NAME, 'op', TG_OP, 'id', COALESCE(NEW.id_, OLD.id_))::text);
+				RETURN NULL;
+			END
+			$func$`,
+		"CREATE OR REPLACE TRIGGER " + desc.tableName + "onNotify AFTER INSERT OR UPDATE OR DELETE ON " + desc.tableName + " FOR EACH ROW EXECUTE FUNCTION on_yo_db_obj_notify()",
+	} {
+		stmt := new(sqlStmt)
+		(*str.Buf)(stmt).WriteString(sql_raw)
+		ret = append(ret, stmt)
+	}
+	return
+}
+
+func schemaHasNotifyTrigger(ctx *Ctx, desc *structDesc) bool {
+	stmt := new(sqlStmt)
+	(*str.Buf)(stmt).WriteString("SELECT COUNT(*) FROM pg_trigger WHERE tgname = @N")
+	return *doSelect[int64](ctx, true, stmt, dbArgs{"N": str.Lo(desc.tableName + "onNotify")}, 1)[0] > 0
+}
+
+func schemaNotifyDropTrigger(desc *structDesc) *sqlStmt {
+	stmt := new(sqlStmt)
+	(*str.Buf)(stmt).WriteString("DROP TRIGGER IF EXISTS " + desc.tableName + "onNotify ON " + desc.tableName)
+	return stmt
+}
+
+// schemaNotifyRenameDropTrigger is for tables renamed via `Ensure`'s `oldTableName`, whose old-named trigger
+// would otherwise keep NOTIFYing alongside the new-named one.
+func schemaNotifyRenameDropTrigger(desc *structDesc) *sqlStmt {
+	stmt := new(sqlStmt)
+	(*str.Buf)(stmt).WriteString("DROP TRIGGER IF EXISTS " + desc.mig.oldTableName + "onNotify ON " + desc.tableName)
+	return stmt
+}
@@ -0,0 +1,161 @@
+package yodb
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"reflect"
+
+	. "yo/cfg"
+	. "yo/ctx"
+	q "yo/db/query"
+	yojson "yo/json"
+	. "yo/util"
+	"yo/util/kv"
+	"yo/util/sl"
+	"yo/util/str"
+)
+
+// Paged is keyset pagination over any number of `orderBy` fields (which must all be `q.F`s, or expressions such as
+// `FullTextRank`), always tie-broken on `Id` (appended if not already in `orderBy`), so that no rows are skipped or
+// repeated even on non-unique or nullable fields. `pageTok` is "" for the first page, thereafter the previous call's `nextPageTok`, an
+// opaque signed string safe to hand out to clients. A `nextPageTok` of "" means there are no more pages.
+func Paged[T any](ctx *Ctx, query q.Query, limit int, pageTok string, orderBy ...q.OrderBy) (resultsPage []*T, nextPageTok string) {
+	desc := desc[T]()
+	if limit <= 0 {
+		panic("Paged on " + desc.tableName + ": limit must be > 0")
+	}
+	for _, o := range orderBy {
+		if (o.Field() == "") && (o.Expr() == nil) {
+			panic("Paged on " + desc.tableName + ": orderBy must be over fields or expressions, not columns")
+		}
+	}
+	if sl.IdxWhere(orderBy, func(it q.OrderBy) bool { return it.Field() == FieldID }) < 0 {
+		orderBy = append(orderBy, FieldID.Asc())
+	}
+
+	if pageTok != "" {
+		query = queryAnd(query, pageTokQuery(desc, orderBy, pageTokLoad(desc, orderBy, pageTok)))
+	}
+	if resultsPage = FindMany[T](ctx, query, limit, nil, orderBy...); len(resultsPage) == limit {
+		last := resultsPage[len(resultsPage)-1]
+		nextPageTok = pageTokFrom(desc, orderBy, sl.As(orderBy, func(it q.OrderBy) any {
+			if expr := it.Expr(); expr != nil {
+				return pageTokExprVal(ctx, desc, expr, reflFieldValueOf(last, FieldID))
+			}
+			val := reflFieldValueOf(last, it.Field())
+			if rv := reflect.ValueOf(val); (val != nil) && (rv.Kind() == reflect.Pointer) && rv.IsNil() {
+				val = nil
+			}
+			return val
+		}))
+	}
+	return
+}
+
+// pageTokExprVal selects the value of `expr` for the row of `id`, since unlike fields it isn't in the results.
+func pageTokExprVal(ctx *Ctx, desc *structDesc, expr q.Operand, id any) any {
+	args, stmt := dbArgs{"Id": id}, new(sqlStmt)
+	w := (*str.Buf)(stmt)
+	w.WriteString("SELECT ")
+	q.OperandSql(w, expr, func(fld q.F) q.C { return desc.colNameOfField(fld) }, args)
+	w.WriteString(" AS val_ FROM " + desc.tableName + " WHERE " + string(ColID) + " = @Id")
+	if rows := doSelect[kv.Any](ctx, true, stmt, args, 1, "val_"); len(rows) > 0 {
+		return (*rows[0])["val_"]
+	}
+	return nil
+}
+
+// pageTokOperand is what `o` orders by.
+func pageTokOperand(o q.OrderBy) q.Operand {
+	if expr := o.Expr(); expr != nil {
+		return expr
+	}
+	return o.Field()
+}
+
+// pageTokQuery gives the "comes after `vals`" condition for the (PG-default) ordering of NULLs last when ASC, first when DESC.
+func pageTokQuery(desc *structDesc, orderBy []q.OrderBy, vals []any) q.Query {
+	var ors []q.Query
+	for i, o := range orderBy {
+		var after q.Query
+		fld, val := pageTokOperand(o), vals[i]
+		switch {
+		case (val == nil) && o.Desc():
+			after = fld.NotEqual(nil)
+		case (val == nil) && !o.Desc():
+			continue // only more NULLs to come
+		case o.Desc():
+			after = fld.LessThan(val)
+		default:
+			after = q.EitherOr(fld.GreaterThan(val), fld.Equal(nil))
+		}
+		conds := make([]q.Query, 0, i+1)
+		for j := 0; j < i; j++ {
+			conds = append(conds, pageTokOperand(orderBy[j]).Equal(vals[j]))
+		}
+		ors = append(ors, q.AllTrue(append(conds, after)...))
+	}
+	if len(ors) == 0 {
+		return ColID.Equal(0) // never true: nothing comes after the all-NULLs last page
+	}
+	return q.EitherOr(ors...)
+}
+
+// pageTokSignKeyEnsure fails `Init` (rather than the first paged request) if there's no `YO_DB_PAGE_TOK_SIGN_KEY` outside dev mode.
+func pageTokSignKeyEnsure() {
+	if (Cfg.YO_DB_PAGE_TOK_SIGN_KEY == "") && !IsDevMode {
+		panic("missing in env: YO_DB_PAGE_TOK_SIGN_KEY")
+	}
+}
+
+func pageTokSig(desc *structDesc, orderBy []q.OrderBy, jsonSrc []byte) []byte {
+	key := If(Cfg.YO_DB_PAGE_TOK_SIGN_KEY == "", "yo_devmode", Cfg.YO_DB_PAGE_TOK_SIGN_KEY) // only empty in dev mode, see `pageTokSignKeyEnsure`
+	mac := hmac.New(sha256.New, []byte(key))
+	_, _ = mac.Write([]byte(desc.tableName))
+	for _, o := range orderBy {
+		if expr := o.Expr(); expr != nil {
+			var buf str.Buf
+			args := dbArgs{}
+			q.OperandSql(&buf, expr, func(fld q.F) q.C { return q.C(fld) }, args)
+			_, _ = mac.Write([]byte(" " + buf.String() + str.GoLike(args) + If(o.Desc(), " DESC", " ASC")))
+		} else {
+			_, _ = mac.Write([]byte(" " + string(o.Field()) + If(o.Desc(), " DESC", " ASC")))
+		}
+	}
+	_, _ = mac.Write([]byte("\n"))
+	_, _ = mac.Write(jsonSrc)
+	return mac.Sum(nil)
+}
+
+func pageTokFrom(desc *structDesc, orderBy []q.OrderBy, vals []any) string {
+	json_src, enc := yojson.From(vals, false), base64.RawURLEncoding
+	return enc.EncodeToString(json_src) + "." + enc.EncodeToString(pageTokSig(desc, orderBy, json_src))
+}
+
+func pageTokLoad(desc *structDesc, orderBy []q.OrderBy, pageTok string) (ret []any) {
+	enc := base64.RawURLEncoding
+	tok_json, tok_sig, ok := str.Cut(pageTok, ".")
+	json_src, err1 := enc.DecodeString(tok_json)
+	sig, err2 := enc.DecodeString(tok_sig)
+	if (!ok) || (err1 != nil) || (err2 != nil) || !hmac.Equal(sig, pageTokSig(desc, orderBy, json_src)) {
+		panic(ErrQuery_ExpectedValidPageTok)
+	}
+
+	var raws []yojson.Raw
+	yojson.Load(json_src, &raws)
+	if len(raws) != len(orderBy) {
+		panic(ErrQuery_ExpectedValidPageTok)
+	}
+	for i, o := range orderBy {
+		ty := reflect.TypeFor[any]() // for `Expr`s, whatever `pageTokExprVal` got
+		if o.Expr() == nil {
+			ty = desc.fieldTypeOfField(o.Field())
+		}
+		ptr := reflect.New(ty)
+		yojson.Load(raws[i], ptr.Interface())
+		val := ptr.Elem()
+		ret = append(ret, If(((val.Kind() == reflect.Pointer) && val.IsNil()), nil, val.Interface()))
+	}
+	return
+}
@@ -0,0 +1,69 @@
+package yodb
+
+import (
+	"reflect"
+	"unsafe"
+
+	. "yo/ctx"
+	q "yo/db/query"
+	"yo/util/sl"
+)
+
+type refPreloader interface {
+	preload(ctx *Ctx, refs []any)
+}
+
+// Preload loads, for each of the given `Ref` fields, all their `recs`' referenced objects in one
+// query per field into the `Ref`s, so that their subsequent `Get` calls don't each query the DB.
+// Returns `recs` for chaining, as in `yodb.Preload(ctx, yodb.FindMany[Foo](ctx, ...), FooBar, FooBaz)`. (Not a `FindMany` option:
+// its variadic `orderBy` leaves no room for one without breaking every call site, and this way it serves `Paged`, `Each` etc. too.)
+func Preload[T any, TFld q.Field](ctx *Ctx, recs []*T, refFields ...TFld) []*T {
+	desc := desc[T]()
+	for _, ref_field := range refFields {
+		field_type := desc.fieldTypeOfField(ref_field.F())
+		if (!sl.Has(desc.fields, ref_field.F())) || !isDbRefType(field_type) {
+			panic("Preload on " + desc.tableName + ": '" + string(ref_field) + "' is not a Ref field")
+		}
+		refs := make([]any, 0, len(recs))
+		for _, rec := range recs {
+			refs = append(refs, refFieldPtr(rec, ref_field.F()))
+		}
+		reflect.New(field_type).Interface().(refPreloader).preload(ctx, refs)
+	}
+	return recs
+}
+
+// PreloadReverse loads the "has-many" reverse relation of `parents`: all `TChild`s whose `Ref` field `childRefField` refers
+// to any of them (and also match `query`, which may be `nil`), in one query, grouped by parent `Id`. The children's
+// `childRefField` `Ref`s get their parent preloaded. For example: `yodb.PreloadReverse[JobRun, JobTask](ctx, jobRuns, JobTaskJobRun, nil)`.
+func PreloadReverse[TParent any, TChild any, TFld q.Field](ctx *Ctx, parents []*TParent, childRefField TFld, query q.Query, orderBy ...q.OrderBy) map[I64][]*TChild {
+	desc_parent, desc_child := desc[TParent](), desc[TChild]()
+	if field_type := desc_child.fieldTypeOfField(childRefField.F()); (!sl.Has(desc_child.fields, childRefField.F())) ||
+		(!isDbRefType(field_type)) || (refDesc(field_type) != desc_parent) {
+		panic("PreloadReverse on " + desc_child.tableName + ": '" + string(childRefField) + "' is not a Ref field to " + desc_parent.tableName)
+	}
+	ret, by_id := make(map[I64][]*TChild, len(parents)), make(map[I64]*TParent, len(parents))
+	for _, parent := range parents {
+		by_id[reflFieldValueOf(parent, FieldID).(I64)] = parent
+	}
+	if len(by_id) == 0 {
+		return ret
+	}
+	ids := make(sl.Of[I64], 0, len(by_id))
+	for id := range by_id {
+		ids = append(ids, id)
+	}
+	for _, child := range FindMany[TChild](ctx, queryAnd(query, childRefField.F().In(ids.ToAnys()...)), 0, nil, orderBy...) {
+		ref := refFieldPtr(child, childRefField.F())
+		parent_id := ref.(dbRef).Id()
+		ref.(interface{ setSelf(any) }).setSelf(by_id[parent_id])
+		ret[parent_id] = append(ret[parent_id], child)
+	}
+	return ret
+}
+
+// refFieldPtr returns the address of the (even if unexported) `Ref` field `fieldName` of `rec`, a `*T`.
+func refFieldPtr(rec any, fieldName q.F) any {
+	field := reflect.ValueOf(rec).Elem().FieldByName(string(fieldName))
+	return reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Interface()
+}
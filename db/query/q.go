@@ -2,7 +2,9 @@ package q
 
 import (
 	"reflect"
+	"unsafe"
 
+	yojson "yo/json"
 	. "yo/util"
 	"yo/util/sl"
 	"yo/util/str"
@@ -13,22 +15,25 @@ import (
 type Operator string
 
 const (
-	OpEq       Operator = " = "
-	OpNeq      Operator = " != "
-	OpLt       Operator = " < "
-	OpLeq      Operator = " <= "
-	OpGt       Operator = " > "
-	OpGeq      Operator = " >= "
-	OpIn       Operator = " IN "
-	OpNotIn    Operator = " NOT IN "
-	OpInArr    Operator = OpEq + opArrAny
-	OpNotInArr Operator = OpNeq + opArrAll
-	OpAnd      Operator = " AND "
-	OpOr       Operator = " OR "
-	OpNot      Operator = "NOT "
-	opArrAll   Operator = " ALL" // note: must be same strlen as opArrAny
-	opArrAny   Operator = " ANY" // note: must be same strlen as opArrAll
-	opDot      Operator = "."    // non-SQL, runtime-Eval only
+	OpEq           Operator = " = "
+	OpNeq          Operator = " != "
+	OpLt           Operator = " < "
+	OpLeq          Operator = " <= "
+	OpGt           Operator = " > "
+	OpGeq          Operator = " >= "
+	OpIn           Operator = " IN "
+	OpNotIn        Operator = " NOT IN "
+	OpInArr        Operator = OpEq + opArrAny
+	OpNotInArr     Operator = OpNeq + opArrAll
+	OpAnd          Operator = " AND "
+	OpOr           Operator = " OR "
+	OpNot          Operator = "NOT "
+	OpTsMatch      Operator = " @@ "
+	OpJsonContains Operator = " @> "
+	OpJsonHasKey   Operator = " ? "
+	opArrAll       Operator = " ALL" // note: must be same strlen as opArrAny
+	opArrAny       Operator = " ANY" // note: must be same strlen as opArrAll
+	opDot          Operator = "."    // non-SQL, runtime-Eval only
 )
 
 var opFlips = map[Operator]Operator{
@@ -51,6 +56,8 @@ func (me C) In(set ...any) Query             { return In(me, set...) }
 func (me C) NotIn(set ...any) Query          { return NotIn(me, set...) }
 func (me C) InArr(arr any) Query             { return InArr(me, arr) }
 func (me C) NotInArr(arr any) Query          { return NotInArr(me, arr) }
+func (me C) JsonContains(json any) Query     { return JsonContains(me, json) }
+func (me C) JsonHasKey(key string) Query     { return JsonHasKey(me, key) }
 func (me C) Asc() OrderBy                    { return &orderBy[C]{col: me} }
 func (me C) Desc() OrderBy                   { return &orderBy[C]{col: me, desc: true} }
 func (me C) Eval(obj any, c2f func(C) F) any { return c2f(me).Eval(obj, c2f) }
@@ -69,16 +76,32 @@ func (me F) In(set ...any) Query            { return In(me, set...) }
 func (me F) NotIn(set ...any) Query         { return NotIn(me, set...) }
 func (me F) InArr(arr any) Query            { return InArr(me, arr) }
 func (me F) NotInArr(arr any) Query         { return NotInArr(me, arr) }
+func (me F) JsonContains(json any) Query    { return JsonContains(me, json) }
+func (me F) JsonHasKey(key string) Query    { return JsonHasKey(me, key) }
 func (me F) Asc() OrderBy                   { return &orderBy[F]{fld: me} }
 func (me F) Desc() OrderBy                  { return &orderBy[F]{fld: me, desc: true} }
 func (me F) Eval(it any, c2f func(C) F) (ret any) {
-	ret = ReflField(it, string(me)).Interface()
+	if field_valuer, _ := it.(FieldValuer); field_valuer != nil {
+		return field_valuer.FieldValue(me)
+	}
+	rv := *ReflField(it, string(me))
+	if !rv.CanInterface() { // unexported field
+		rv = reflect.NewAt(rv.Type(), unsafe.Pointer(rv.UnsafeAddr())).Elem()
+	}
+	ret = rv.Interface()
 	if ref, is_ref := ret.(DbRef); is_ref {
 		return ref.IdRaw()
 	}
 	return ret
 }
 
+// FieldValuer lets the `obj` passed to `Query.Eval` supply its own field values instead of those obtained by reflection,
+// and normalize the query's own operand values likewise (as does yodb's in-memory backend, for its own comparison semantics).
+type FieldValuer interface {
+	FieldValue(F) any
+	OperandValue(any) any
+}
+
 type DbRef interface {
 	IsDbRef() bool
 	IdRaw() int64
@@ -97,7 +120,12 @@ func (me V) In(set ...any) Query            { return In(me, set...) }
 func (me V) NotIn(set ...any) Query         { return NotIn(me, set...) }
 func (me V) InArr(arr any) Query            { return InArr(me, arr) }
 func (me V) NotInArr(arr any) Query         { return NotInArr(me, arr) }
-func (me V) Eval(any, func(C) F) any        { return me.Value }
+func (me V) Eval(obj any, _ func(C) F) any {
+	if field_valuer, _ := obj.(FieldValuer); field_valuer != nil {
+		return field_valuer.OperandValue(me.Value)
+	}
+	return me.Value
+}
 
 func That(value any) Operand { return V{value} }
 func isNull(value any) bool {
@@ -110,10 +138,17 @@ func isNull(value any) bool {
 type fn string
 
 const (
-	FnStrLen fn = "octet_length"
-	FnArrLen fn = "array_length"
+	FnStrLen   fn = "octet_length"
+	FnArrLen   fn = "array_length"
+	FnJsonPath fn = "jsonb_extract_path_text" // args: the path keys (or array indices) as strings
 )
 
+// FnTsQuery is only for `TsMatch` (taking the text-search config before the search terms), so not in the above codegen'd `Operand` methods.
+const FnTsQuery fn = "websearch_to_tsquery"
+
+// FnTsRank is only for `TsRank`, likewise.
+const FnTsRank fn = "ts_rank"
+
 type fun struct {
 	Fn   fn
 	Args []Operand
@@ -142,6 +177,8 @@ func (me *fun) In(set ...any) Query            { return In(me, set...) }
 func (me *fun) NotIn(set ...any) Query         { return NotIn(me, set...) }
 func (me *fun) InArr(arr any) Query            { return InArr(me, arr) }
 func (me *fun) NotInArr(arr any) Query         { return NotInArr(me, arr) }
+func (me *fun) Asc() OrderBy                   { return &orderBy[C]{expr: me} }
+func (me *fun) Desc() OrderBy                  { return &orderBy[C]{expr: me, desc: true} }
 func (me *fun) Eval(obj any, c2f func(C) F) any {
 	if me.Alt != nil {
 		return me.Alt(sl.As(me.Args, func(it Operand) any { return it.Eval(obj, c2f) })...)
@@ -153,6 +190,27 @@ func (me *fun) Eval(obj any, c2f func(C) F) any {
 	case FnStrLen:
 		str := me.Args[0].Eval(obj, c2f).(string)
 		return len(str)
+	case FnJsonPath:
+		json := evalJson(me.Args[0].Eval(obj, c2f))
+		for _, arg := range me.Args[1:] {
+			key := str.Fmt("%v", arg.Eval(obj, c2f))
+			switch it := json.(type) {
+			case map[string]any:
+				json = it[key]
+			case []any:
+				if idx, err := str.ToI64(key, 10, 0); (err == nil) && (idx >= 0) && (idx < int64(len(it))) {
+					json = it[idx]
+				} else {
+					json = nil
+				}
+			default:
+				json = nil
+			}
+		}
+		if s, is := json.(string); is || (json == nil) {
+			return If[any](is, s, nil)
+		}
+		return string(yojson.From(json, false))
 	default:
 		panic(me.Fn)
 	}
@@ -161,18 +219,28 @@ func (me *fun) Eval(obj any, c2f func(C) F) any {
 type OrderBy interface {
 	Col() C
 	Field() F
+	Expr() Operand // if not `nil`, ordering by this (eg. a `TsRank`) instead of by `Col` or `Field`
 	Desc() bool
 }
 
 type orderBy[T ~string] struct {
 	col  C
 	fld  F
+	expr Operand
 	desc bool
 }
 
-func (me *orderBy[T]) Desc() bool { return me.desc }
-func (me *orderBy[T]) Col() C     { return me.col }
-func (me *orderBy[T]) Field() F   { return me.fld }
+func (me *orderBy[T]) Desc() bool    { return me.desc }
+func (me *orderBy[T]) Col() C        { return me.col }
+func (me *orderBy[T]) Field() F      { return me.fld }
+func (me *orderBy[T]) Expr() Operand { return me.expr }
+
+// Sortable is an `Operand` that can also be ordered by, such as a `TsRank`.
+type Sortable interface {
+	Operand
+	Asc() OrderBy
+	Desc() OrderBy
+}
 
 type Query interface {
 	And(...Query) Query
@@ -228,6 +296,20 @@ func ArrAreAny(arr any, operator Operator, arg any) Query {
 func ArrAreAll(arr any, operator Operator, arg any) Query {
 	return &query{op: operator + opArrAll, operands: operandsFrom(arg, arr)}
 }
+func TsMatch(tsVector any, tsConfig string, searchTerms any) Query {
+	return &query{op: OpTsMatch, operands: operandsFrom(tsVector, Fn(FnTsQuery, tsConfig, searchTerms))}
+}
+
+// TsRank is the rank of `tsVector` matching the `TsMatch` of the same `tsConfig` and `searchTerms`, higher being better.
+func TsRank(tsVector any, tsConfig string, searchTerms any) Sortable {
+	return Fn(FnTsRank, tsVector, Fn(FnTsQuery, tsConfig, searchTerms)).(*fun)
+}
+func JsonContains(lhs any, json any) Query {
+	return &query{op: OpJsonContains, operands: operandsFrom(lhs, string(yojson.From(json, false)))}
+}
+func JsonHasKey(lhs any, key string) Query {
+	return &query{op: OpJsonHasKey, operands: operandsFrom(lhs, key)}
+}
 func AllTrue(conds ...Query) Query {
 	if conds = sl.Without(conds, nil); len(conds) == 0 {
 		panic("q.AllTrue reached the no-conds situation, double-check call-site and prototyped q.AllTrue impl")
@@ -315,43 +397,46 @@ func (me *query) And(conds ...Query) Query { return AllTrue(append([]Query{me},
 func (me *query) Or(conds ...Query) Query  { return EitherOr(append([]Query{me}, conds...)...) }
 func (me *query) Not() Query               { return Not(me) }
 
-func (me *query) Sql(buf *str.Buf, fld2col func(F) C, args pgx.NamedArgs, isForDbgPrintOnly bool) {
-	var do_arg func(operand Operand)
-	do_arg = func(operand Operand) {
-		if sub_stmt, _ := operand.(stmt); sub_stmt != nil {
-			sub_stmt.Sql(buf)
-		} else if col_name, is := operand.(C); is {
-			buf.WriteString(string(col_name))
-		} else if fld, is := operand.(field); is {
-			buf.WriteString(string(fld2col(fld.F())))
-		} else if v, is := operand.(V); is {
-			if v.Value == true {
-				buf.WriteString("(true::boolean)")
-			} else if v.Value == false {
-				buf.WriteString("(false::boolean)")
-			} else {
-				arg_name := "@A" + str.FromInt(len(args))
-				args[arg_name[1:]] = v.Value
-				buf.WriteString(arg_name)
-				buf.WriteByte(' ')
-			}
-		} else if fn, _ := operand.(*fun); fn != nil {
-			buf.WriteString(string(fn.Fn))
-			buf.WriteByte('(')
-			for i, arg := range fn.Args {
-				if i > 0 {
-					buf.WriteByte(',')
-				}
-				do_arg(arg)
-			}
-			if fn.Fn == FnArrLen {
-				buf.WriteString(",1")
-			}
-			buf.WriteByte(')')
+// OperandSql writes the SQL expression of `operand` into `buf`, as `Query.Sql` does for each of its operands
+// (for use outside `WHERE`s, such as for ordering by an `OrderBy.Expr`).
+func OperandSql(buf *str.Buf, operand Operand, fld2col func(F) C, args pgx.NamedArgs) {
+	if sub_stmt, _ := operand.(stmt); sub_stmt != nil {
+		sub_stmt.Sql(buf)
+	} else if col_name, is := operand.(C); is {
+		buf.WriteString(string(col_name))
+	} else if fld, is := operand.(field); is {
+		buf.WriteString(string(fld2col(fld.F())))
+	} else if v, is := operand.(V); is {
+		if v.Value == true {
+			buf.WriteString("(true::boolean)")
+		} else if v.Value == false {
+			buf.WriteString("(false::boolean)")
 		} else {
-			panic(str.Fmt("%T being %#v", operand, operand))
+			arg_name := "@A" + str.FromInt(len(args))
+			args[arg_name[1:]] = v.Value
+			buf.WriteString(arg_name)
+			buf.WriteByte(' ')
+		}
+	} else if fn, _ := operand.(*fun); fn != nil {
+		buf.WriteString(string(fn.Fn))
+		buf.WriteByte('(')
+		for i, arg := range fn.Args {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			OperandSql(buf, arg, fld2col, args)
+		}
+		if fn.Fn == FnArrLen {
+			buf.WriteString(",1")
 		}
+		buf.WriteByte(')')
+	} else {
+		panic(str.Fmt("%T being %#v", operand, operand))
 	}
+}
+
+func (me *query) Sql(buf *str.Buf, fld2col func(F) C, args pgx.NamedArgs, isForDbgPrintOnly bool) {
+	do_arg := func(operand Operand) { OperandSql(buf, operand, fld2col, args) }
 
 	if (str.Trim(string(me.op)) == "") || ((len(me.conds) == 0) && (len(me.operands) == 0)) ||
 		((len(me.conds) != 0) && (len(me.operands) != 0)) ||
@@ -467,12 +552,14 @@ func (me *query) Eval(obj any, c2f func(C) F) (falseDueTo Query) {
 	case OpNot:
 		return If[Query]((me.conds[0].Eval(obj, c2f) == nil), me, nil)
 	case OpIn:
-		in_set := sl.Has(sl.As(me.operands, func(it Operand) any { return it.Eval(obj, c2f) }),
-			me.operands[0].Eval(obj, c2f))
+		in_set := sl.Any(me.operands[1:], func(it Operand) bool {
+			return (&query{op: OpEq, operands: []Operand{me.operands[0], it}}).Eval(obj, c2f) == nil
+		})
 		return If[Query](in_set, nil, me)
 	case OpNotIn:
-		in_set := sl.Has(sl.As(me.operands, func(it Operand) any { return it.Eval(obj, c2f) }),
-			me.operands[0].Eval(obj, c2f))
+		in_set := sl.Any(me.operands[1:], func(it Operand) bool {
+			return (&query{op: OpEq, operands: []Operand{me.operands[0], it}}).Eval(obj, c2f) == nil
+		})
 		return If[Query](in_set, me, nil)
 	case OpEq:
 		return If[Query](is_eq(), nil, me)
@@ -486,6 +573,21 @@ func (me *query) Eval(obj any, c2f func(C) F) (falseDueTo Query) {
 		return If[Query](ReflLt(reflect.ValueOf(me.operands[0].Eval(obj, c2f)), reflect.ValueOf(me.operands[1].Eval(obj, c2f))), nil, me)
 	case OpLeq:
 		return If[Query](ReflLe(reflect.ValueOf(me.operands[0].Eval(obj, c2f)), reflect.ValueOf(me.operands[1].Eval(obj, c2f))), nil, me)
+	case OpJsonContains:
+		var rhs any
+		yojson.Load([]byte(me.operands[1].Eval(obj, c2f).(string)), &rhs)
+		return If[Query](evalJsonContains(evalJson(me.operands[0].Eval(obj, c2f)), rhs, true), nil, me)
+	case OpJsonHasKey:
+		key, has := me.operands[1].Eval(obj, c2f).(string), false
+		switch it := evalJson(me.operands[0].Eval(obj, c2f)).(type) {
+		case map[string]any:
+			_, has = it[key]
+		case []any:
+			has = sl.Has(it, any(key))
+		case string:
+			has = (it == key)
+		}
+		return If[Query](has, nil, me)
 	default:
 		is_arr_all, is_arr_any := str.Ends(string(me.op), string(opArrAll)), str.Ends(string(me.op), string(opArrAny))
 		if is_arr_all || is_arr_any {
@@ -508,6 +610,43 @@ func (me *query) Eval(obj any, c2f func(C) F) (falseDueTo Query) {
 	}
 }
 
+func evalNull(value any) bool {
+	rv := reflect.ValueOf(value)
+	return (!rv.IsValid()) || (sl.Has([]reflect.Kind{reflect.Pointer, reflect.Map, reflect.Slice}, rv.Kind()) && rv.IsNil())
+}
+
+// evalJson turns `value` into what it is in jsonb: `nil`, `bool`, `float64`, `string`, `[]any` or `map[string]any`
+func evalJson(value any) (ret any) {
+	if !evalNull(value) {
+		yojson.Load(yojson.From(value, false), &ret)
+	}
+	return
+}
+
+// evalJsonContains is jsonb's `@>`
+func evalJsonContains(lhs any, rhs any, isTopLevel bool) bool {
+	switch rhs := rhs.(type) {
+	case map[string]any:
+		lhs, is := lhs.(map[string]any)
+		for k, v := range rhs {
+			if lhs_v, has := lhs[k]; !(is && has && evalJsonContains(lhs_v, v, false)) {
+				return false
+			}
+		}
+		return is
+	case []any:
+		lhs, is := lhs.([]any)
+		return is && sl.All(rhs, func(rhs_item any) bool {
+			return sl.Any(lhs, func(lhs_item any) bool { return evalJsonContains(lhs_item, rhs_item, false) })
+		})
+	default:
+		if lhs, is := lhs.([]any); is && isTopLevel { // a top-level array contains a primitive if it has it as an item
+			return sl.Any(lhs, func(lhs_item any) bool { return evalJsonContains(lhs_item, rhs, false) })
+		}
+		return reflect.DeepEqual(lhs, rhs)
+	}
+}
+
 func (me *query) AllDottedFs() map[F][]string {
 	ret := map[F][]string{}
 	for _, operand := range me.operands {
@@ -1,8 +1,11 @@
 package q
 
-func (me C) StrLen(args ...any) Operand { return Fn(FnStrLen, append([]any{me}, args...)...) }
-func (me F) StrLen(args ...any) Operand { return Fn(FnStrLen, append([]any{me}, args...)...) }
-func (me V) StrLen(args ...any) Operand { return Fn(FnStrLen, append([]any{me}, args...)...) }
-func (me C) ArrLen(args ...any) Operand { return Fn(FnArrLen, append([]any{me}, args...)...) }
-func (me F) ArrLen(args ...any) Operand { return Fn(FnArrLen, append([]any{me}, args...)...) }
-func (me V) ArrLen(args ...any) Operand { return Fn(FnArrLen, append([]any{me}, args...)...) }
+func (me C) StrLen(args ...any) Operand   { return Fn(FnStrLen, append([]any{me}, args...)...) }
+func (me F) StrLen(args ...any) Operand   { return Fn(FnStrLen, append([]any{me}, args...)...) }
+func (me V) StrLen(args ...any) Operand   { return Fn(FnStrLen, append([]any{me}, args...)...) }
+func (me C) ArrLen(args ...any) Operand   { return Fn(FnArrLen, append([]any{me}, args...)...) }
+func (me F) ArrLen(args ...any) Operand   { return Fn(FnArrLen, append([]any{me}, args...)...) }
+func (me V) ArrLen(args ...any) Operand   { return Fn(FnArrLen, append([]any{me}, args...)...) }
+func (me C) JsonPath(args ...any) Operand { return Fn(FnJsonPath, append([]any{me}, args...)...) }
+func (me F) JsonPath(args ...any) Operand { return Fn(FnJsonPath, append([]any{me}, args...)...) }
+func (me V) JsonPath(args ...any) Operand { return Fn(FnJsonPath, append([]any{me}, args...)...) }
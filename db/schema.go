@@ -30,11 +30,19 @@ func GetTable(ctx *Ctx, tableName string) []*TableColumn {
 	stmt := new(sqlStmt).selCols(desc, nil, true).
 		fromAndJoinAndWhereAndOrderBy(desc, false, q.C("table_name").Equal(tableName), args,
 			q.C("table_name").Asc(), q.C("ordinal_position").Asc())
-	return doSelect[TableColumn](ctx, stmt, args, 0)
+	return doSelect[TableColumn](ctx, true, stmt, args, 0)
 }
 
+const idxUsingGin = " USING GIN"
+
 func schemaReCreateIndices(desc *structDesc, renamesOldColToNewField map[q.C]q.F) (ret []*sqlStmt) {
 	indexed_cols_and_order := map[q.C]string{ColCreatedAt: "DESC", ColModifiedAt: "DESC"}
+	if desc.constraints.softDelete != nil {
+		indexed_cols_and_order[ColDeletedAt] = "DESC"
+	}
+	if len(desc.constraints.fullText) > 0 {
+		indexed_cols_and_order[ColFullText] = idxUsingGin
+	}
 	for i, field_name := range desc.fields { // always index foreign-key cols for ON DELETE trigger perf
 		if sl.Has(desc.constraints.uniques, field_name) {
 			continue // uniques always auto-indexed by default
@@ -49,7 +57,7 @@ func schemaReCreateIndices(desc *structDesc, renamesOldColToNewField map[q.C]q.F
 			continue // uniques always auto-indexed by default
 		}
 		field, _ := desc.ty.FieldByName(string(field_name))
-		order_by := If(field.Type == tyDateTime, "DESC", "")
+		order_by := If(field.Type == tyDateTime, "DESC", If(isDbJsonType(field.Type), idxUsingGin, "")) // GIN for `JsonContains` and `JsonHasKey`
 		col_name := desc.colNameOfField(field_name)
 		indexed_cols_and_order[col_name] = order_by
 	}
@@ -76,9 +84,12 @@ func schemaReCreateIndices(desc *structDesc, renamesOldColToNewField map[q.C]q.F
 		w(index_names[col_name])
 		w(" ON ")
 		w(desc.tableName)
+		if order_by == idxUsingGin {
+			w(idxUsingGin)
+		}
 		w(" (")
 		w(string(col_name))
-		if order_by != "" {
+		if (order_by != "") && (order_by != idxUsingGin) {
 			w(" ")
 			w(order_by)
 			w(" NULLS LAST")
@@ -113,6 +124,15 @@ func schemaCreateTable(desc *structDesc, didWriteUpdTriggerFuncYet *bool) (ret [
 				w(sqlColTypeDeclFrom(field.Type, is_unique))
 			}
 		}
+		if desc.constraints.softDelete != nil {
+			w(",\n\t")
+			w(string(ColDeletedAt))
+			w(" timestamp without time zone NULL DEFAULT (NULL)")
+		}
+		if len(desc.constraints.fullText) > 0 {
+			w(",\n\t")
+			w(schemaFullTextColDecl(desc))
+		}
 		w("\n)")
 		ret = append(ret, stmt_create_table)
 	}
@@ -148,6 +168,7 @@ func schemaCreateTable(desc *structDesc, didWriteUpdTriggerFuncYet *bool) (ret [
 	}
 
 	ret = append(ret, schemaReCreateIndices(desc, nil)...)
+	ret = append(ret, schemaReCreateChecks(desc)...)
 	return
 }
 
@@ -156,24 +177,43 @@ var sqlDtAltNames = map[string]Text{
 	"int4":   "integer",
 	"int8":   "bigint",
 	"float4": "real",
+	"float8": "double precision",
 	"text[]": "ARRAY",
 }
 
+// column type changes doable via a plain `ALTER COLUMN ... TYPE ... USING` without any loss of data
+var sqlDtSafeWidenings = map[Text][]string{
+	"smallint":         {"int4", "int8", "float4", "float8", "text"},
+	"integer":          {"int8", "float8", "text"},
+	"bigint":           {"text"},
+	"real":             {"float8", "text"},
+	"double precision": {"text"},
+	"boolean":          {"text"},
+}
+
 func init() {
 	for k := range sqlDtAltNames {
 		sqlDtAltNames[k+"[]"] = "ARRAY"
 	}
 }
 
-func schemaAlterTable(desc *structDesc, curTable []*TableColumn) (ret []*sqlStmt) {
+// schemaAlterTable returns in `convs` the `ColConv`s to run after `ret` (which renames their old columns to `ColConv.srcCol` and
+// adds the new-typed ones), and in `retAfterMigs` the statements to run after those and any pending `Migrations` (dropped columns,
+// including those `ColConv.srcCol`s, and indices re-creation).
+func schemaAlterTable(desc *structDesc, curTable []*TableColumn) (ret []*sqlStmt, convs []ColConv, retAfterMigs []*sqlStmt) {
 	if desc.mig.oldTableName == desc.tableName {
 		panic("invalid table rename: " + desc.mig.oldTableName)
 	}
 
-	cols_gone, fields_new, col_type_changes := []q.C{}, []q.F{}, []q.C{}
+	cols_gone, fields_new, col_type_changes, has_col_full_text := []q.C{}, []q.F{}, []q.C{}, false
 	for _, table_col := range curTable {
 		col_name := q.C(table_col.ColumnName)
-		if (col_name == ColID) || (col_name == ColCreatedAt) || (col_name == ColModifiedAt) {
+		if (col_name == ColFullText) && !sl.Has(desc.cols, ColFullText) {
+			has_col_full_text = true
+			continue
+		}
+		if (col_name == ColID) || (col_name == ColCreatedAt) || (col_name == ColModifiedAt) ||
+			((col_name == ColDeletedAt) && (desc.constraints.softDelete != nil)) {
 			continue
 		}
 		if !sl.Has(desc.cols, col_name) {
@@ -181,8 +221,13 @@ func schemaAlterTable(desc *structDesc, curTable []*TableColumn) (ret []*sqlStmt
 		} else if field, ok := desc.ty.FieldByName(string(desc.fields[sl.IdxWhere(desc.cols, func(it q.C) bool { return (it == col_name) })])); !ok {
 			panic("impossible")
 		} else if sql_type_name := sqlColTypeFrom(field.Type); (sql_type_name != string(table_col.DataType)) && (sqlDtAltNames[sql_type_name] != table_col.DataType) {
-			col_type_changes = append(col_type_changes, col_name)
-			panic(string(col_name) + "<<<oldDT:" + string(table_col.DataType) + ">>>newDT:" + sql_type_name)
+			if idx := sl.IdxWhere(desc.mig.colConvs, func(it ColConv) bool { return it.Col == col_name }); idx >= 0 {
+				convs = append(convs, desc.mig.colConvs[idx])
+			} else if sl.Has(sqlDtSafeWidenings[table_col.DataType], sql_type_name) || sl.Has(desc.mig.ackDestructive, col_name) {
+				col_type_changes = append(col_type_changes, col_name)
+			} else {
+				panic(desc.tableName + ": refusing to change type of column '" + string(col_name) + "' from '" + string(table_col.DataType) + "' to '" + sql_type_name + "' without either `ColConvs` or `AckDestructive`")
+			}
 		}
 	}
 	for i, struct_col_name := range desc.cols {
@@ -215,11 +260,62 @@ func schemaAlterTable(desc *structDesc, curTable []*TableColumn) (ret []*sqlStmt
 		ret = append(ret, stmt)
 	}
 
-	if (len(cols_gone) > 0) || (len(fields_new) > 0) || (len(col_type_changes) > 0) {
+	for _, conv := range desc.mig.colConvs {
+		if !sl.Any(convs, func(it ColConv) bool { return it.Col == conv.Col }) {
+			panic(desc.tableName + ": outdated column conversion: '" + string(conv.Col) + "'")
+		}
+	}
+	for _, conv := range convs { // old column renamed out of the way, new-typed one added for `ColConv.run` to fill
 		stmt := new(sqlStmt)
 		w := (*str.Buf)(stmt).WriteString
 		w("ALTER TABLE ")
 		w(desc.tableName)
+		w(" RENAME COLUMN ")
+		w(string(conv.Col))
+		w(" TO ")
+		w(string(conv.srcCol()))
+		ret = append(ret, stmt)
+
+		stmt = new(sqlStmt)
+		w = (*str.Buf)(stmt).WriteString
+		w("ALTER TABLE ")
+		w(desc.tableName)
+		w(" \n\tADD COLUMN ")
+		w(string(conv.Col))
+		w(" ")
+		w(sqlColTypeDeclFrom(desc.fieldTypeOfCol(conv.Col), false)) // any `Unique` only after conversion, not while all rows have the default
+		ret = append(ret, stmt)
+
+		stmt = new(sqlStmt)
+		w = (*str.Buf)(stmt).WriteString
+		w("ALTER TABLE ")
+		w(desc.tableName)
+		w(" \n\tDROP COLUMN ")
+		w(string(conv.srcCol()))
+		if sl.Has(desc.constraints.uniques, desc.fieldNameOfCol(conv.Col)) {
+			w(", \n\tADD UNIQUE (")
+			w(string(conv.Col))
+			w(")")
+		}
+		retAfterMigs = append(retAfterMigs, stmt)
+	}
+	for _, col_name := range cols_gone {
+		if !sl.Has(desc.mig.ackDestructive, col_name) {
+			panic(desc.tableName + ": refusing to drop column '" + string(col_name) + "' without `AckDestructive`")
+		}
+	}
+
+	add_col_deleted_at := (desc.constraints.softDelete != nil) && !sl.Any(curTable, func(it *TableColumn) bool { return it.ColumnName == Text(ColDeletedAt) })
+	if (len(fields_new) > 0) || (len(col_type_changes) > 0) || add_col_deleted_at {
+		stmt := new(sqlStmt)
+		w := (*str.Buf)(stmt).WriteString
+		w("ALTER TABLE ")
+		w(desc.tableName)
+		if add_col_deleted_at {
+			w(" \n\tADD COLUMN IF NOT EXISTS ")
+			w(string(ColDeletedAt))
+			w(" timestamp without time zone NULL DEFAULT (NULL),")
+		}
 		for _, field_name := range fields_new {
 			col_name := desc.colNameOfField(field_name)
 			w(" \n\tADD COLUMN IF NOT EXISTS ")
@@ -230,9 +326,16 @@ func schemaAlterTable(desc *structDesc, curTable []*TableColumn) (ret []*sqlStmt
 			w(sqlColTypeDeclFrom(field.Type, is_unique))
 			w(",")
 		}
-		for _, col_name := range cols_gone {
-			w(" \n\tDROP COLUMN IF EXISTS ")
+		for _, col_name := range col_type_changes {
+			sql_type_name := sqlColTypeFrom(desc.fieldTypeOfCol(col_name))
+			w(" \n\tALTER COLUMN ")
+			w(string(col_name))
+			w(" TYPE ")
+			w(sql_type_name)
+			w(" USING ")
 			w(string(col_name))
+			w("::")
+			w(sql_type_name)
 			w(",")
 		}
 		ret = append(ret, stmt)
@@ -251,14 +354,78 @@ func schemaAlterTable(desc *structDesc, curTable []*TableColumn) (ret []*sqlStmt
 			ret = append(ret, stmt)
 		}
 	}
+	ret = append(ret, schemaRenameConstraints(desc)...)
 
-	if (len(ret) > 0) || desc.mig.constraintsChanged { // alterations pertinent, re-create all indices
-		ret = append(ret, schemaReCreateIndices(desc, desc.mig.renamesOldColToNewField)...)
+	if is_full_text := (len(desc.constraints.fullText) > 0); (is_full_text && ((!has_col_full_text) || desc.mig.constraintsChanged)) ||
+		((!is_full_text) && has_col_full_text) { // generated column, so no `AckDestructive` needed to (re)create or drop it
+		stmt := new(sqlStmt)
+		w := (*str.Buf)(stmt).WriteString
+		w("ALTER TABLE ")
+		w(desc.tableName)
+		w(" \n\tDROP COLUMN IF EXISTS ")
+		w(string(ColFullText))
+		if is_full_text {
+			w(", \n\tADD COLUMN ")
+			w(schemaFullTextColDecl(desc))
+		}
+		ret = append(ret, stmt)
+	}
+
+	if len(cols_gone) > 0 {
+		stmt := new(sqlStmt)
+		w := (*str.Buf)(stmt).WriteString
+		w("ALTER TABLE ")
+		w(desc.tableName)
+		for _, col_name := range cols_gone {
+			w(" \n\tDROP COLUMN IF EXISTS ")
+			w(string(col_name))
+			w(",")
+		}
+		retAfterMigs = append(retAfterMigs, stmt)
+	}
+
+	if (len(ret) > 0) || (len(convs) > 0) || (len(retAfterMigs) > 0) || desc.mig.constraintsChanged { // alterations pertinent, re-create all indices and checks
+		retAfterMigs = append(retAfterMigs, schemaReCreateIndices(desc, desc.mig.renamesOldColToNewField)...)
+		retAfterMigs = append(retAfterMigs, schemaReCreateChecks(desc)...)
 	}
 
 	return
 }
 
+// schemaRenameConstraints renames the implicitly-named `Unique` and `Ref` constraints of columns (or tables) renamed via `Ensure`,
+// which Postgres leaves under their old names, to what `registerConstraintErrs` expects from the current names.
+func schemaRenameConstraints(desc *structDesc) (ret []*sqlStmt) {
+	old_table_name := If(desc.mig.oldTableName != "", desc.mig.oldTableName, desc.tableName)
+	rename := func(colName q.C, label string) {
+		old_col_name := colName
+		for old, new_field_name := range desc.mig.renamesOldColToNewField {
+			if desc.colNameOfField(new_field_name) == colName {
+				old_col_name = old
+			}
+		}
+		if old_name, new_name := pgObjName(old_table_name, string(old_col_name), label), pgObjName(desc.tableName, string(colName), label); old_name != new_name {
+			stmt := new(sqlStmt) // the constraint might not exist yet if only now made `Unique`
+			(*str.Buf)(stmt).WriteString(str.Repl(`DO $do$
+		BEGIN
+			IF EXISTS (SELECT 1 FROM pg_constraint WHERE (conrelid = '{table_name}'::regclass) AND (conname = '{old_name}')) THEN
+				ALTER TABLE {table_name} RENAME CONSTRAINT {old_name} TO {new_name};
+			END IF;
+		END
+		$do$`, str.Dict{"table_name": str.Lo(desc.tableName), "old_name": old_name, "new_name": new_name}))
+			ret = append(ret, stmt)
+		}
+	}
+	for _, field_name := range desc.constraints.uniques {
+		rename(desc.colNameOfField(field_name), "key")
+	}
+	for i, field_name := range desc.fields[3:] {
+		if isDbRefType(desc.fieldTypeOfField(field_name)) {
+			rename(desc.cols[3+i], "fkey")
+		}
+	}
+	return
+}
+
 func sqlColTypeFrom(ty reflect.Type) string {
 	switch ty {
 	case tyBool:
@@ -291,33 +458,42 @@ func sqlColTypeFrom(ty reflect.Type) string {
 	}
 }
 
+// sqlColNullable returns whether `sqlColTypeDeclFrom` declares columns of `ty` as `NULL`able (else `NOT NULL`).
+func sqlColNullable(ty reflect.Type) bool {
+	switch ty {
+	case tyBool, tyF32, tyF64, tyI16, tyI32, tyI64, tyI8, tyU16, tyU32, tyU8, tyText:
+		return false
+	}
+	return true
+}
+
 func sqlColTypeDeclFrom(ty reflect.Type, isUnique bool) string {
-	sql_data_type_name := sqlColTypeFrom(ty)
+	sql_data_type_name := sqlColTypeFrom(ty) + If(sqlColNullable(ty), " NULL", " NOT NULL")
 	unique_maybe := If(isUnique, " UNIQUE", "")
 	switch ty {
 	case tyBool:
-		return sql_data_type_name + " NOT NULL DEFAULT (false)" + unique_maybe
+		return sql_data_type_name + " DEFAULT (false)" + unique_maybe
 	case tyBytes, tyDateTime:
-		return sql_data_type_name + " NULL DEFAULT (NULL)" + unique_maybe
+		return sql_data_type_name + " DEFAULT (NULL)" + unique_maybe
 	case tyF32, tyF64, tyI16, tyI32, tyI64, tyI8, tyU16, tyU32, tyU8:
-		return sql_data_type_name + " NOT NULL DEFAULT (0)" + unique_maybe
+		return sql_data_type_name + " DEFAULT (0)" + unique_maybe
 	case tyText:
-		return sql_data_type_name + " NOT NULL DEFAULT ('')" + unique_maybe
+		return sql_data_type_name + " DEFAULT ('')" + unique_maybe
 	default:
 		if is_db_json_dict_type, is_db_json_arr_type, is_db_json_obj_type := isWhatDbJsonType(ty); is_db_json_obj_type || is_db_json_dict_type || is_db_json_arr_type {
-			return sql_data_type_name + " NULL DEFAULT (NULL)" + unique_maybe
+			return sql_data_type_name + " DEFAULT (NULL)" + unique_maybe
 		} else if isDbRefType(ty) {
 			dummy := reflect.New(ty).Interface().(interface {
 				structDesc() *structDesc
 				refOnDel
 			})
 			desc := dummy.structDesc()
-			return sql_data_type_name + " NULL DEFAULT (NULL)" + unique_maybe + " REFERENCES " + desc.tableName + " ON DELETE " + dummy.onDelSql()
+			return sql_data_type_name + " DEFAULT (NULL)" + unique_maybe + " REFERENCES " + desc.tableName + " ON DELETE " + dummy.onDelSql()
 		} else if isDbArrType(ty) {
 			if unique_maybe != "" {
 				panic("unique constraint on '" + ty.String() + "'")
 			}
-			return sql_data_type_name + " NULL DEFAULT NULL"
+			return sql_data_type_name + " DEFAULT NULL"
 		}
 		panic(ty)
 	}
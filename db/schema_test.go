@@ -0,0 +1,32 @@
+package yodb
+
+import (
+	"testing"
+
+	q "yo/db/query"
+	"yo/util/str"
+)
+
+func TestRenameConstraintsStmts(t *testing.T) {
+	desc_parent, desc_child := *desc[testInMemParent](), *desc[testInMemChild]()
+	if stmts := schemaRenameConstraints(&desc_parent); len(stmts) != 0 {
+		t.Errorf("expected no renames without table or column renames, got %d", len(stmts))
+	}
+	desc_parent.mig.renamesOldColToNewField = map[q.C]q.F{"title_": "Name"}
+	stmts := schemaRenameConstraints(&desc_parent)
+	if expect := "RENAME CONSTRAINT test_in_mem_parent__title__key TO test_in_mem_parent__name__key;"; (len(stmts) != 1) || !str.Has(stmts[0].String(), expect) {
+		t.Errorf("expected %s, got: %v", expect, stmts)
+	}
+
+	desc_child.mig.oldTableName = "test_in_mem_old"
+	stmts = schemaRenameConstraints(&desc_child)
+	for i, expect := range []string{
+		"RENAME CONSTRAINT test_in_mem_old_cascade__fkey TO test_in_mem_child__cascade__fkey;",
+		"RENAME CONSTRAINT test_in_mem_old_set_null__fkey TO test_in_mem_child__set_null__fkey;",
+		"RENAME CONSTRAINT test_in_mem_old_prevent__fkey TO test_in_mem_child__prevent__fkey;",
+	} {
+		if (i >= len(stmts)) || !str.Has(stmts[i].String(), expect) {
+			t.Errorf("expected %s, got: %v", expect, stmts)
+		}
+	}
+}
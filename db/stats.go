@@ -0,0 +1,112 @@
+package yodb
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	. "yo/cfg"
+	. "yo/ctx"
+	yolog "yo/log"
+	. "yo/util"
+	"yo/util/kv"
+	"yo/util/sl"
+	"yo/util/str"
+)
+
+const dbStatsMaxSlowQueriesPerCtx = 8
+
+// dbStatsRecord adds the just-completed query to `ctx.Db.Stats` and, if it took at least `YO_DB_SLOW_QUERY_THRESHOLD`,
+// logs it (args redacted) and keeps it in `ctx.Db.Stats.SlowQueries`. `timeNotInDb` is any time spent in `doStream` callbacks.
+// Failed queries get recorded too. Only if `isRead` (as known by the caller, not guessed from `sqlRaw`) can it be `dbExplain`ed.
+func dbStatsRecord(ctx *Ctx, sqlRaw string, args dbArgs, timeStarted time.Time, timeNotInDb time.Duration, numRows int64, isRead bool) {
+	duration := time.Since(timeStarted) - timeNotInDb
+	stats := &ctx.Db.Stats
+	stats.NumQueries, stats.NumRows, stats.Duration = stats.NumQueries+1, stats.NumRows+numRows, stats.Duration+duration
+	if (Cfg.YO_DB_SLOW_QUERY_THRESHOLD <= 0) || (duration < Cfg.YO_DB_SLOW_QUERY_THRESHOLD) {
+		return
+	}
+
+	slow_query := str.Fmt("slow query (%s, %d rows): %s %s", str.DurationMs(int64(duration)), numRows, sqlRaw, dbArgsRedacted(args))
+	if Cfg.YO_DB_SLOW_QUERY_EXPLAIN && isRead && (ctx.Db.Tx == nil) && !ctx.Db.ExplainAnalyze { // not in TXs: any failure would abort them
+		Try(func() {
+			slow_query += "\n" + dbExplain(ctx, dbForReads(ctx).QueryContext, "", sqlRaw, args)
+		}, func(err any) {
+			slow_query += str.Fmt("\nEXPLAIN failed: %v", err)
+		})
+	}
+	yolog.Println("db: %s", slow_query)
+	if len(stats.SlowQueries) < dbStatsMaxSlowQueriesPerCtx {
+		stats.SlowQueries = append(stats.SlowQueries, slow_query)
+	}
+}
+
+// dbArgsRedacted shows only the types of `args`, never their values
+func dbArgsRedacted(args dbArgs) string {
+	return "{" + str.Join(sl.As(sl.Sorted(kv.Keys(args)), func(arg_name string) string {
+		return arg_name + ":" + If(args[arg_name] == nil, "NULL", str.Fmt("%T", args[arg_name]))
+	}), ", ") + "}"
+}
+
+// dbExplainAnalyzeIfFlagged logs and keeps in `ctx.Db.Stats.Explained` the `dbExplainAnalyze` output of the just-run query, if `ctx.Db.ExplainAnalyze`.
+func dbExplainAnalyzeIfFlagged(ctx *Ctx, sqlRaw string, args dbArgs, isRead bool) {
+	if !ctx.Db.ExplainAnalyze {
+		return
+	}
+	explained := "EXPLAIN (ANALYZE, BUFFERS) " + sqlRaw + " " + dbArgsRedacted(args) + "\n"
+	Try(func() {
+		explained += dbExplainAnalyze(ctx, sqlRaw, args, isRead)
+	}, func(err any) {
+		explained += str.Fmt("failed: %v", err)
+	})
+	yolog.Println("db: %s", explained)
+	if stats := &ctx.Db.Stats; len(stats.Explained) < dbStatsMaxSlowQueriesPerCtx {
+		stats.Explained = append(stats.Explained, explained)
+	}
+}
+
+// dbExplainAnalyze runs the (already `dbArgsCleanUpForPgx`ed) query a second time as `EXPLAIN (ANALYZE, BUFFERS)`, inside a TX
+// (or, if `ctx` is already in one, a SAVEPOINT) that always gets rolled back, so that any writes by it do not persist.
+func dbExplainAnalyze(ctx *Ctx, sqlRaw string, args dbArgs, isRead bool) string {
+	const opts = "(ANALYZE, BUFFERS) "
+	if ctx.Db.Tx != nil {
+		if _, err := ctx.Db.Tx.ExecContext(ctx, "SAVEPOINT yo_explain_"); err != nil {
+			panic(err)
+		}
+		defer func() {
+			_, _ = ctx.Db.Tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT yo_explain_")
+			_, _ = ctx.Db.Tx.ExecContext(ctx, "RELEASE SAVEPOINT yo_explain_")
+		}()
+		return dbExplain(ctx, ctx.Db.Tx.QueryContext, opts, sqlRaw, args)
+	}
+	tx, err := If(isRead, dbForReads(ctx), DB).BeginTx(ctx, nil)
+	if err != nil {
+		panic(err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	return dbExplain(ctx, tx.QueryContext, opts, sqlRaw, args)
+}
+
+// dbExplain returns the plan of the (already `dbArgsCleanUpForPgx`ed) query via `EXPLAIN` with `opts`: if those are empty
+// (as for `YO_DB_SLOW_QUERY_EXPLAIN`), without ANALYZE, so that the (already slow) query does not get executed a second time.
+func dbExplain(ctx *Ctx, doQuery func(context.Context, string, ...any) (*sql.Rows, error), opts string, sqlRaw string, args dbArgs) string {
+	rows, err := doQuery(ctx, "EXPLAIN "+opts+sqlRaw, args)
+	if rows != nil {
+		defer rows.Close()
+	}
+	if err != nil {
+		panic(err)
+	}
+	var lines []string
+	for rows.Next() {
+		var line string
+		if err = rows.Scan(&line); err != nil {
+			panic(err)
+		}
+		lines = append(lines, line)
+	}
+	if err = rows.Err(); err != nil {
+		panic(err)
+	}
+	return str.Join(lines, "\n")
+}
@@ -33,6 +33,16 @@ func (me *sqlStmt) delete(from string) *sqlStmt {
 	return me
 }
 
+func (me *sqlStmt) setDeleted(desc *structDesc, deleted bool) *sqlStmt {
+	w := (*str.Buf)(me).WriteString
+	w("UPDATE ")
+	w(desc.tableName)
+	w(" SET ")
+	w(string(ColDeletedAt))
+	w(If(deleted, " = now()", " = NULL"))
+	return me
+}
+
 func (me *sqlStmt) insertViaUnnest(desc *structDesc, needRetIdsForInserts bool, cols ...q.C) *sqlStmt {
 	w := (*str.Buf)(me).WriteString
 	if len(cols) == 0 {
@@ -68,6 +78,43 @@ func (me *sqlStmt) insertViaUnnest(desc *structDesc, needRetIdsForInserts bool,
 	return me
 }
 
+func (me *sqlStmt) insertViaUnnestUpsertAppendum(desc *structDesc, conflictCol q.C, overwriteCols []q.C) *sqlStmt {
+	w := (*str.Buf)(me).WriteString
+	w(" ON CONFLICT (")
+	w(string(conflictCol))
+	w(") DO ")
+	if restore := (desc.constraints.softDelete != nil); len(overwriteCols) == 0 {
+		if !restore {
+			w("NOTHING")
+			return me
+		}
+		w("UPDATE SET " + string(ColDeletedAt) + " = NULL WHERE (" + desc.tableName + "." + string(ColDeletedAt) + " IS NOT NULL)")
+		return me
+	} else if restore {
+		overwriteCols = append(overwriteCols[:len(overwriteCols):len(overwriteCols)], ColDeletedAt)
+	}
+	w("UPDATE SET ")
+	for i, col_name := range overwriteCols {
+		if i > 0 {
+			w(", ")
+		}
+		w(string(col_name))
+		w(" = ")
+		if col_name == ColDeletedAt {
+			w("NULL")
+		} else if desc.fieldNameOfCol(col_name) == desc.constraints.versioned {
+			w(desc.tableName)
+			w(".")
+			w(string(col_name))
+			w(" + 1")
+		} else {
+			w("EXCLUDED.")
+			w(string(col_name))
+		}
+	}
+	return me
+}
+
 func (me *sqlStmt) insert(desc *structDesc, numRows int, upsert bool, needRetIdsForInserts bool, cols ...q.C) *sqlStmt {
 	w := (*str.Buf)(me).WriteString
 	w("INSERT INTO ")
@@ -116,6 +163,9 @@ func (me *sqlStmt) insert(desc *structDesc, numRows int, upsert bool, needRetIds
 		w(")")
 	}
 
+	if upsert && (desc.constraints.softDelete != nil) { // restore conflicting soft-deleted rows rather than updating them while invisible
+		non_unique_cols, non_unique_vals = append(non_unique_cols, string(ColDeletedAt)), append(non_unique_vals, "NULL")
+	}
 	if upsert && (len(desc.constraints.uniques) > 0) {
 		me.insertUpsertAppendum(desc, non_unique_cols, non_unique_vals)
 	} else if needRetIdsForInserts {
@@ -175,7 +225,10 @@ func (me *sqlStmt) update(desc *structDesc, colNames ...q.C) *sqlStmt {
 		}
 		w(string(col_name))
 		w(" = ")
-		if isDbJsonType(field.Type) {
+		if field_name == desc.constraints.versioned {
+			w(string(col_name))
+			w(" + 1 ")
+		} else if isDbJsonType(field.Type) {
 			w("jsonb_strip_nulls(@")
 			w(string(col_name))
 			w(" )")
@@ -255,6 +308,7 @@ func (me *sqlStmt) limit(max int) *sqlStmt {
 }
 
 func (me *sqlStmt) fromAndJoinAndWhereAndOrderBy(desc *structDesc, isMut bool, where q.Query, args pgx.NamedArgs, orderBy ...q.OrderBy) *sqlStmt {
+	where, with_deleted := withDeletedFrom(where)
 	joins := map[q.F]Pair[string, *structDesc]{}
 	var f2c func(*structDesc, q.F, bool) q.C
 	f2c = func(d *structDesc, fieldName q.F, noTableName bool) q.C {
@@ -305,16 +359,34 @@ func (me *sqlStmt) fromAndJoinAndWhereAndOrderBy(desc *structDesc, isMut bool, w
 			w(string(ColID))
 			w(" = ")
 			w(string(f2c(desc, field_name, false)))
+			if (sub_desc.constraints.softDelete != nil) && !with_deleted { // as if soft-deleted referees were gone
+				w(" AND ")
+				w(join_name)
+				w(".")
+				w(string(ColDeletedAt))
+				w(" IS NULL")
+			}
 			w(" ")
 			idx_join++
 		}
 	}
 
+	var where_sql str.Buf
 	if where != nil {
-		w(" WHERE (")
-		where.Sql((*str.Buf)(me), func(fld q.F) q.C {
+		where.Sql(&where_sql, func(fld q.F) q.C {
 			return f2c(desc, fld, false)
 		}, args, false)
+	}
+	if (desc.constraints.softDelete != nil) && !with_deleted {
+		w(" WHERE (" + desc.tableName + "." + string(ColDeletedAt) + " IS NULL)")
+		if where_sql.Len() > 0 {
+			w(" AND (")
+			w(where_sql.String())
+			w(")")
+		}
+	} else if where_sql.Len() > 0 {
+		w(" WHERE (")
+		w(where_sql.String())
 		w(")")
 	}
 	if len(orderBy) > 0 {
@@ -323,7 +395,9 @@ func (me *sqlStmt) fromAndJoinAndWhereAndOrderBy(desc *structDesc, isMut bool, w
 			if i > 0 {
 				w(", ")
 			}
-			if fld := o.Field(); fld != "" {
+			if expr := o.Expr(); expr != nil {
+				q.OperandSql((*str.Buf)(me), expr, func(fld q.F) q.C { return f2c(desc, fld, false) }, args)
+			} else if fld := o.Field(); fld != "" {
 				w(string(f2c(desc, fld, false)))
 			} else {
 				w(desc.tableName)
@@ -0,0 +1,27 @@
+package yodb
+
+import (
+	"testing"
+
+	q "yo/db/query"
+	"yo/util/str"
+)
+
+type testStmtTrashRef struct {
+	Id     I64
+	DtMade *DateTime
+	DtMod  *DateTime
+
+	Trashable Ref[testInMemTrashable, RefOnDelCascade]
+}
+
+func TestJoinSoftDeleteStmts(t *testing.T) {
+	desc, on_cond := desc[testStmtTrashRef](), " ON _j_0.id_ = test_stmt_trash_ref_.trashable_ AND _j_0.dt_del_ IS NULL "
+	query := q.F("Trashable.Name").Equal("foo")
+	if sql := new(sqlStmt).fromAndJoinAndWhereAndOrderBy(desc, false, query, dbArgs{}).String(); !str.Has(sql, on_cond) {
+		t.Errorf("expected %s, got: %s", on_cond, sql)
+	}
+	if sql := new(sqlStmt).fromAndJoinAndWhereAndOrderBy(desc, false, WithDeleted[testStmtTrashRef](query), dbArgs{}).String(); str.Has(sql, on_cond) || !str.Has(sql, " ON _j_0.id_ = test_stmt_trash_ref_.trashable_ ") {
+		t.Errorf("expected no soft-delete condition, got: %s", sql)
+	}
+}
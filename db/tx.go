@@ -0,0 +1,79 @@
+package yodb
+
+import (
+	"database/sql"
+	"errors"
+	"math/rand"
+	"time"
+
+	. "yo/cfg"
+	. "yo/ctx"
+	. "yo/util"
+	"yo/util/str"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const txMaxAttemptsDefault = 4
+
+type TxOpts struct {
+	Serious     bool // SERIALIZABLE if true, else REPEATABLE READ (as with `Ctx.DbTx`)
+	MaxAttempts int  // if 0, `Cfg.YO_DB_TX_MAX_ATTEMPTS`, if also 0, 4
+}
+
+// InTx runs `do` in a new DB transaction on `ctx`, committed right after `do` returns. On serialization failures
+// or deadlocks (from `do` or the commit), the transaction is rolled back and `do` re-run from scratch (after a short,
+// growing and jittered backoff) up to `opts.MaxAttempts` times in total. Any other panic is rolled back and re-panicked.
+// So `do` must not have side effects outside the DB (or only idempotent ones). If `ctx` already has a transaction, `do`
+// simply runs in it, once. Retries are recorded as `ctx.Timings` steps.
+func InTx(ctx *Ctx, opts TxOpts, do func(*Ctx)) {
+	if (ctx.Db.Tx != nil) || (DB == nil) { // no `DB` with `InitInMem`
+		do(ctx)
+		return
+	}
+	max_attempts := If(opts.MaxAttempts > 0, opts.MaxAttempts, If(Cfg.YO_DB_TX_MAX_ATTEMPTS > 0, Cfg.YO_DB_TX_MAX_ATTEMPTS, txMaxAttemptsDefault))
+	for attempt := 1; ; attempt++ {
+		fail := txAttempt(ctx, opts, do)
+		if fail == nil {
+			return
+		}
+		if (attempt >= max_attempts) || !TxRetryable(fail) {
+			panic(fail)
+		}
+		ctx.Timings.Step("InTx retry " + str.FromInt(attempt) + "/" + str.FromInt(max_attempts-1))
+		backoff := time.Duration(attempt*attempt)*(10*time.Millisecond) + time.Duration(rand.Int63n(int64(10*time.Millisecond)))
+		select {
+		case <-ctx.Done():
+			panic(fail)
+		case <-time.After(backoff):
+		}
+	}
+}
+
+func txAttempt(ctx *Ctx, opts TxOpts, do func(*Ctx)) (fail any) {
+	tx, err := DB.BeginTx(ctx, &sql.TxOptions{Isolation: If(opts.Serious, sql.LevelSerializable, sql.LevelRepeatableRead)})
+	if err != nil {
+		panic(err)
+	}
+	ctx.Db.Tx = tx
+	defer func() {
+		if ctx.Db.Tx = nil; fail != nil {
+			_ = tx.Rollback()
+		}
+	}()
+	Try(func() { do(ctx) }, func(err any) { fail = err })
+	if fail == nil {
+		if err := tx.Commit(); err != nil {
+			fail = err
+		}
+	}
+	return
+}
+
+// TxRetryable reports whether `fail` (a recovered panic) is a serialization failure or deadlock that `InTx` retries on.
+func TxRetryable(fail any) bool {
+	var pg_err *pgconn.PgError
+	err, _ := fail.(error)
+	return (err != nil) && errors.As(err, &pg_err) &&
+		((pg_err.Code == "40001" /* serialization_failure */) || (pg_err.Code == "40P01" /* deadlock_detected */))
+}
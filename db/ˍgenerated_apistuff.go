@@ -18,9 +18,11 @@ func api[TIn any, TOut any](f func(*yosrv.ApiCtx[TIn, TOut]), failIfs ...yosrv.F
 	return yosrv.Api[TIn, TOut](f, failIfs...).From(yodbPkg)
 }
 
+const ErrQuery_ExpectedFullTextTypeForFTS util.Err = "Query_ExpectedFullTextTypeForFTS"
 const ErrQuery_ExpectedOneOrNoneButNotMultipleOfFldOrStrOrBoolOrInt util.Err = "Query_ExpectedOneOrNoneButNotMultipleOfFldOrStrOrBoolOrInt"
 const ErrQuery_ExpectedOnlyEitherQueryOrQueryFromButNotBoth util.Err = "Query_ExpectedOnlyEitherQueryOrQueryFromButNotBoth"
 const ErrQuery_ExpectedSetOperandForIN util.Err = "Query_ExpectedSetOperandForIN"
+const ErrQuery_ExpectedStrOperandForFTS util.Err = "Query_ExpectedStrOperandForFTS"
 const ErrQuery_ExpectedTwoOperandsForAND util.Err = "Query_ExpectedTwoOperandsForAND"
 const ErrQuery_ExpectedTwoOperandsForEQ util.Err = "Query_ExpectedTwoOperandsForEQ"
 const ErrQuery_ExpectedTwoOperandsForGE util.Err = "Query_ExpectedTwoOperandsForGE"
@@ -31,15 +33,31 @@ const ErrQuery_ExpectedTwoOperandsForLT util.Err = "Query_ExpectedTwoOperandsFor
 const ErrQuery_ExpectedTwoOperandsForNE util.Err = "Query_ExpectedTwoOperandsForNE"
 const ErrQuery_ExpectedTwoOperandsForNOT util.Err = "Query_ExpectedTwoOperandsForNOT"
 const ErrQuery_ExpectedTwoOperandsForOR util.Err = "Query_ExpectedTwoOperandsForOR"
+const ErrQuery_ExpectedValidPageTok util.Err = "Query_ExpectedValidPageTok"
+const ErrAggregate_ExpectedAggsOrGroupBy util.Err = "Aggregate_ExpectedAggsOrGroupBy"
+const ErrAggregate_ExpectedFieldForAggFn util.Err = "Aggregate_ExpectedFieldForAggFn"
+const ErrAggregate_ExpectedKnownAggFn util.Err = "Aggregate_ExpectedKnownAggFn"
+const ErrAggregate_ExpectedValidAndUniqueAggNames util.Err = "Aggregate_ExpectedValidAndUniqueAggNames"
 const ErrDbDelete_ExpectedQueryForDelete util.Err = "DbDelete_ExpectedQueryForDelete"
+const ErrExport_ExpectedCsvOrNdjsonFormat util.Err = "Export_ExpectedCsvOrNdjsonFormat"
+const ErrExport_ExpectedExportedFields util.Err = "Export_ExpectedExportedFields"
+const ErrDbUpdate_Conflict util.Err = "DbUpdate_Conflict"
 const ErrDbUpdate_ExpectedChangesForUpdate util.Err = "DbUpdate_ExpectedChangesForUpdate"
+const ErrDbUpdate_ExpectedNoQueryForVersionedUpdate util.Err = "DbUpdate_ExpectedNoQueryForVersionedUpdate"
 const ErrDbUpdate_ExpectedQueryForUpdate util.Err = "DbUpdate_ExpectedQueryForUpdate"
+const ___yo_db_ErrEntry_aggregateAggs = q.F("Aggs")
+const ___yo_db_ErrEntry_aggregateGroupBy = q.F("GroupBy")
+const ___yo_db_ErrEntry_aggregateMax = q.F("Max")
+const ___yo_db_ErrEntry_aggregateOrderBy = q.F("OrderBy")
+const ___yo_db_ErrEntry_aggregateQuery = q.F("Query")
+const ___yo_db_ErrEntry_aggregateQueryFrom = q.F("QueryFrom")
 const ___yo_db_ErrEntry_countMax = q.F("Max")
 const ___yo_db_ErrEntry_countOrderBy = q.F("OrderBy")
 const ___yo_db_ErrEntry_countQuery = q.F("Query")
 const ___yo_db_ErrEntry_countQueryFrom = q.F("QueryFrom")
 const ___yo_db_ErrEntry_createManyItems = q.F("Items")
 const ___yo_db_ErrEntry_createOneCtxVals = q.F("CtxVals")
+const ___yo_db_ErrEntry_createOneDbStats = q.F("DbStats")
 const ___yo_db_ErrEntry_createOneDbTx = q.F("DbTx")
 const ___yo_db_ErrEntry_createOneDtMade = q.F("DtMade")
 const ___yo_db_ErrEntry_createOneDtMod = q.F("DtMod")
@@ -56,11 +74,22 @@ const ___yo_db_ErrEntry_deleteManyOrderBy = q.F("OrderBy")
 const ___yo_db_ErrEntry_deleteManyQuery = q.F("Query")
 const ___yo_db_ErrEntry_deleteManyQueryFrom = q.F("QueryFrom")
 const ___yo_db_ErrEntry_deleteOneId = q.F("Id")
+const ___yo_db_ErrEntry_exportFields = q.F("Fields")
+const ___yo_db_ErrEntry_exportFormat = q.F("Format")
+const ___yo_db_ErrEntry_exportMax = q.F("Max")
+const ___yo_db_ErrEntry_exportOrderBy = q.F("OrderBy")
+const ___yo_db_ErrEntry_exportQuery = q.F("Query")
+const ___yo_db_ErrEntry_exportQueryFrom = q.F("QueryFrom")
 const ___yo_db_ErrEntry_findByIdId = q.F("Id")
 const ___yo_db_ErrEntry_findManyMax = q.F("Max")
 const ___yo_db_ErrEntry_findManyOrderBy = q.F("OrderBy")
 const ___yo_db_ErrEntry_findManyQuery = q.F("Query")
 const ___yo_db_ErrEntry_findManyQueryFrom = q.F("QueryFrom")
+const ___yo_db_ErrEntry_findManyPagedMax = q.F("Max")
+const ___yo_db_ErrEntry_findManyPagedOrderBy = q.F("OrderBy")
+const ___yo_db_ErrEntry_findManyPagedPageTok = q.F("PageTok")
+const ___yo_db_ErrEntry_findManyPagedQuery = q.F("Query")
+const ___yo_db_ErrEntry_findManyPagedQueryFrom = q.F("QueryFrom")
 const ___yo_db_ErrEntry_findOneMax = q.F("Max")
 const ___yo_db_ErrEntry_findOneOrderBy = q.F("OrderBy")
 const ___yo_db_ErrEntry_findOneQuery = q.F("Query")
@@ -74,6 +103,12 @@ const ___yo_db_ErrEntry_updateManyQueryFrom = q.F("QueryFrom")
 const ___yo_db_ErrEntry_updateOneChangedFields = q.F("ChangedFields")
 const ___yo_db_ErrEntry_updateOneChanges = q.F("Changes")
 const ___yo_db_ErrEntry_updateOneId = q.F("Id")
+const ___yo_db_JobDef_aggregateAggs = q.F("Aggs")
+const ___yo_db_JobDef_aggregateGroupBy = q.F("GroupBy")
+const ___yo_db_JobDef_aggregateMax = q.F("Max")
+const ___yo_db_JobDef_aggregateOrderBy = q.F("OrderBy")
+const ___yo_db_JobDef_aggregateQuery = q.F("Query")
+const ___yo_db_JobDef_aggregateQueryFrom = q.F("QueryFrom")
 const ___yo_db_JobDef_countMax = q.F("Max")
 const ___yo_db_JobDef_countOrderBy = q.F("OrderBy")
 const ___yo_db_JobDef_countQuery = q.F("Query")
@@ -97,11 +132,22 @@ const ___yo_db_JobDef_deleteManyOrderBy = q.F("OrderBy")
 const ___yo_db_JobDef_deleteManyQuery = q.F("Query")
 const ___yo_db_JobDef_deleteManyQueryFrom = q.F("QueryFrom")
 const ___yo_db_JobDef_deleteOneId = q.F("Id")
+const ___yo_db_JobDef_exportFields = q.F("Fields")
+const ___yo_db_JobDef_exportFormat = q.F("Format")
+const ___yo_db_JobDef_exportMax = q.F("Max")
+const ___yo_db_JobDef_exportOrderBy = q.F("OrderBy")
+const ___yo_db_JobDef_exportQuery = q.F("Query")
+const ___yo_db_JobDef_exportQueryFrom = q.F("QueryFrom")
 const ___yo_db_JobDef_findByIdId = q.F("Id")
 const ___yo_db_JobDef_findManyMax = q.F("Max")
 const ___yo_db_JobDef_findManyOrderBy = q.F("OrderBy")
 const ___yo_db_JobDef_findManyQuery = q.F("Query")
 const ___yo_db_JobDef_findManyQueryFrom = q.F("QueryFrom")
+const ___yo_db_JobDef_findManyPagedMax = q.F("Max")
+const ___yo_db_JobDef_findManyPagedOrderBy = q.F("OrderBy")
+const ___yo_db_JobDef_findManyPagedPageTok = q.F("PageTok")
+const ___yo_db_JobDef_findManyPagedQuery = q.F("Query")
+const ___yo_db_JobDef_findManyPagedQueryFrom = q.F("QueryFrom")
 const ___yo_db_JobDef_findOneMax = q.F("Max")
 const ___yo_db_JobDef_findOneOrderBy = q.F("OrderBy")
 const ___yo_db_JobDef_findOneQuery = q.F("Query")
@@ -115,6 +161,12 @@ const ___yo_db_JobDef_updateManyQueryFrom = q.F("QueryFrom")
 const ___yo_db_JobDef_updateOneChangedFields = q.F("ChangedFields")
 const ___yo_db_JobDef_updateOneChanges = q.F("Changes")
 const ___yo_db_JobDef_updateOneId = q.F("Id")
+const ___yo_db_JobRun_aggregateAggs = q.F("Aggs")
+const ___yo_db_JobRun_aggregateGroupBy = q.F("GroupBy")
+const ___yo_db_JobRun_aggregateMax = q.F("Max")
+const ___yo_db_JobRun_aggregateOrderBy = q.F("OrderBy")
+const ___yo_db_JobRun_aggregateQuery = q.F("Query")
+const ___yo_db_JobRun_aggregateQueryFrom = q.F("QueryFrom")
 const ___yo_db_JobRun_countMax = q.F("Max")
 const ___yo_db_JobRun_countOrderBy = q.F("OrderBy")
 const ___yo_db_JobRun_countQuery = q.F("Query")
@@ -141,11 +193,22 @@ const ___yo_db_JobRun_deleteManyOrderBy = q.F("OrderBy")
 const ___yo_db_JobRun_deleteManyQuery = q.F("Query")
 const ___yo_db_JobRun_deleteManyQueryFrom = q.F("QueryFrom")
 const ___yo_db_JobRun_deleteOneId = q.F("Id")
+const ___yo_db_JobRun_exportFields = q.F("Fields")
+const ___yo_db_JobRun_exportFormat = q.F("Format")
+const ___yo_db_JobRun_exportMax = q.F("Max")
+const ___yo_db_JobRun_exportOrderBy = q.F("OrderBy")
+const ___yo_db_JobRun_exportQuery = q.F("Query")
+const ___yo_db_JobRun_exportQueryFrom = q.F("QueryFrom")
 const ___yo_db_JobRun_findByIdId = q.F("Id")
 const ___yo_db_JobRun_findManyMax = q.F("Max")
 const ___yo_db_JobRun_findManyOrderBy = q.F("OrderBy")
 const ___yo_db_JobRun_findManyQuery = q.F("Query")
 const ___yo_db_JobRun_findManyQueryFrom = q.F("QueryFrom")
+const ___yo_db_JobRun_findManyPagedMax = q.F("Max")
+const ___yo_db_JobRun_findManyPagedOrderBy = q.F("OrderBy")
+const ___yo_db_JobRun_findManyPagedPageTok = q.F("PageTok")
+const ___yo_db_JobRun_findManyPagedQuery = q.F("Query")
+const ___yo_db_JobRun_findManyPagedQueryFrom = q.F("QueryFrom")
 const ___yo_db_JobRun_findOneMax = q.F("Max")
 const ___yo_db_JobRun_findOneOrderBy = q.F("OrderBy")
 const ___yo_db_JobRun_findOneQuery = q.F("Query")
@@ -159,6 +222,12 @@ const ___yo_db_JobRun_updateManyQueryFrom = q.F("QueryFrom")
 const ___yo_db_JobRun_updateOneChangedFields = q.F("ChangedFields")
 const ___yo_db_JobRun_updateOneChanges = q.F("Changes")
 const ___yo_db_JobRun_updateOneId = q.F("Id")
+const ___yo_db_JobTask_aggregateAggs = q.F("Aggs")
+const ___yo_db_JobTask_aggregateGroupBy = q.F("GroupBy")
+const ___yo_db_JobTask_aggregateMax = q.F("Max")
+const ___yo_db_JobTask_aggregateOrderBy = q.F("OrderBy")
+const ___yo_db_JobTask_aggregateQuery = q.F("Query")
+const ___yo_db_JobTask_aggregateQueryFrom = q.F("QueryFrom")
 const ___yo_db_JobTask_countMax = q.F("Max")
 const ___yo_db_JobTask_countOrderBy = q.F("OrderBy")
 const ___yo_db_JobTask_countQuery = q.F("Query")
@@ -180,11 +249,22 @@ const ___yo_db_JobTask_deleteManyOrderBy = q.F("OrderBy")
 const ___yo_db_JobTask_deleteManyQuery = q.F("Query")
 const ___yo_db_JobTask_deleteManyQueryFrom = q.F("QueryFrom")
 const ___yo_db_JobTask_deleteOneId = q.F("Id")
+const ___yo_db_JobTask_exportFields = q.F("Fields")
+const ___yo_db_JobTask_exportFormat = q.F("Format")
+const ___yo_db_JobTask_exportMax = q.F("Max")
+const ___yo_db_JobTask_exportOrderBy = q.F("OrderBy")
+const ___yo_db_JobTask_exportQuery = q.F("Query")
+const ___yo_db_JobTask_exportQueryFrom = q.F("QueryFrom")
 const ___yo_db_JobTask_findByIdId = q.F("Id")
 const ___yo_db_JobTask_findManyMax = q.F("Max")
 const ___yo_db_JobTask_findManyOrderBy = q.F("OrderBy")
 const ___yo_db_JobTask_findManyQuery = q.F("Query")
 const ___yo_db_JobTask_findManyQueryFrom = q.F("QueryFrom")
+const ___yo_db_JobTask_findManyPagedMax = q.F("Max")
+const ___yo_db_JobTask_findManyPagedOrderBy = q.F("OrderBy")
+const ___yo_db_JobTask_findManyPagedPageTok = q.F("PageTok")
+const ___yo_db_JobTask_findManyPagedQuery = q.F("Query")
+const ___yo_db_JobTask_findManyPagedQueryFrom = q.F("QueryFrom")
 const ___yo_db_JobTask_findOneMax = q.F("Max")
 const ___yo_db_JobTask_findOneOrderBy = q.F("OrderBy")
 const ___yo_db_JobTask_findOneQuery = q.F("Query")
@@ -198,6 +278,12 @@ const ___yo_db_JobTask_updateManyQueryFrom = q.F("QueryFrom")
 const ___yo_db_JobTask_updateOneChangedFields = q.F("ChangedFields")
 const ___yo_db_JobTask_updateOneChanges = q.F("Changes")
 const ___yo_db_JobTask_updateOneId = q.F("Id")
+const ___yo_db_MailReq_aggregateAggs = q.F("Aggs")
+const ___yo_db_MailReq_aggregateGroupBy = q.F("GroupBy")
+const ___yo_db_MailReq_aggregateMax = q.F("Max")
+const ___yo_db_MailReq_aggregateOrderBy = q.F("OrderBy")
+const ___yo_db_MailReq_aggregateQuery = q.F("Query")
+const ___yo_db_MailReq_aggregateQueryFrom = q.F("QueryFrom")
 const ___yo_db_MailReq_countMax = q.F("Max")
 const ___yo_db_MailReq_countOrderBy = q.F("OrderBy")
 const ___yo_db_MailReq_countQuery = q.F("Query")
@@ -214,11 +300,22 @@ const ___yo_db_MailReq_deleteManyOrderBy = q.F("OrderBy")
 const ___yo_db_MailReq_deleteManyQuery = q.F("Query")
 const ___yo_db_MailReq_deleteManyQueryFrom = q.F("QueryFrom")
 const ___yo_db_MailReq_deleteOneId = q.F("Id")
+const ___yo_db_MailReq_exportFields = q.F("Fields")
+const ___yo_db_MailReq_exportFormat = q.F("Format")
+const ___yo_db_MailReq_exportMax = q.F("Max")
+const ___yo_db_MailReq_exportOrderBy = q.F("OrderBy")
+const ___yo_db_MailReq_exportQuery = q.F("Query")
+const ___yo_db_MailReq_exportQueryFrom = q.F("QueryFrom")
 const ___yo_db_MailReq_findByIdId = q.F("Id")
 const ___yo_db_MailReq_findManyMax = q.F("Max")
 const ___yo_db_MailReq_findManyOrderBy = q.F("OrderBy")
 const ___yo_db_MailReq_findManyQuery = q.F("Query")
 const ___yo_db_MailReq_findManyQueryFrom = q.F("QueryFrom")
+const ___yo_db_MailReq_findManyPagedMax = q.F("Max")
+const ___yo_db_MailReq_findManyPagedOrderBy = q.F("OrderBy")
+const ___yo_db_MailReq_findManyPagedPageTok = q.F("PageTok")
+const ___yo_db_MailReq_findManyPagedQuery = q.F("Query")
+const ___yo_db_MailReq_findManyPagedQueryFrom = q.F("QueryFrom")
 const ___yo_db_MailReq_findOneMax = q.F("Max")
 const ___yo_db_MailReq_findOneOrderBy = q.F("OrderBy")
 const ___yo_db_MailReq_findOneQuery = q.F("Query")
@@ -232,6 +329,12 @@ const ___yo_db_MailReq_updateManyQueryFrom = q.F("QueryFrom")
 const ___yo_db_MailReq_updateOneChangedFields = q.F("ChangedFields")
 const ___yo_db_MailReq_updateOneChanges = q.F("Changes")
 const ___yo_db_MailReq_updateOneId = q.F("Id")
+const ___yo_db_UserAccount_aggregateAggs = q.F("Aggs")
+const ___yo_db_UserAccount_aggregateGroupBy = q.F("GroupBy")
+const ___yo_db_UserAccount_aggregateMax = q.F("Max")
+const ___yo_db_UserAccount_aggregateOrderBy = q.F("OrderBy")
+const ___yo_db_UserAccount_aggregateQuery = q.F("Query")
+const ___yo_db_UserAccount_aggregateQueryFrom = q.F("QueryFrom")
 const ___yo_db_UserAccount_countMax = q.F("Max")
 const ___yo_db_UserAccount_countOrderBy = q.F("OrderBy")
 const ___yo_db_UserAccount_countQuery = q.F("Query")
@@ -248,11 +351,22 @@ const ___yo_db_UserAccount_deleteManyOrderBy = q.F("OrderBy")
 const ___yo_db_UserAccount_deleteManyQuery = q.F("Query")
 const ___yo_db_UserAccount_deleteManyQueryFrom = q.F("QueryFrom")
 const ___yo_db_UserAccount_deleteOneId = q.F("Id")
+const ___yo_db_UserAccount_exportFields = q.F("Fields")
+const ___yo_db_UserAccount_exportFormat = q.F("Format")
+const ___yo_db_UserAccount_exportMax = q.F("Max")
+const ___yo_db_UserAccount_exportOrderBy = q.F("OrderBy")
+const ___yo_db_UserAccount_exportQuery = q.F("Query")
+const ___yo_db_UserAccount_exportQueryFrom = q.F("QueryFrom")
 const ___yo_db_UserAccount_findByIdId = q.F("Id")
 const ___yo_db_UserAccount_findManyMax = q.F("Max")
 const ___yo_db_UserAccount_findManyOrderBy = q.F("OrderBy")
 const ___yo_db_UserAccount_findManyQuery = q.F("Query")
 const ___yo_db_UserAccount_findManyQueryFrom = q.F("QueryFrom")
+const ___yo_db_UserAccount_findManyPagedMax = q.F("Max")
+const ___yo_db_UserAccount_findManyPagedOrderBy = q.F("OrderBy")
+const ___yo_db_UserAccount_findManyPagedPageTok = q.F("PageTok")
+const ___yo_db_UserAccount_findManyPagedQuery = q.F("Query")
+const ___yo_db_UserAccount_findManyPagedQueryFrom = q.F("QueryFrom")
 const ___yo_db_UserAccount_findOneMax = q.F("Max")
 const ___yo_db_UserAccount_findOneOrderBy = q.F("OrderBy")
 const ___yo_db_UserAccount_findOneQuery = q.F("Query")
@@ -266,6 +380,12 @@ const ___yo_db_UserAccount_updateManyQueryFrom = q.F("QueryFrom")
 const ___yo_db_UserAccount_updateOneChangedFields = q.F("ChangedFields")
 const ___yo_db_UserAccount_updateOneChanges = q.F("Changes")
 const ___yo_db_UserAccount_updateOneId = q.F("Id")
+const ___yo_db_UserPwdReq_aggregateAggs = q.F("Aggs")
+const ___yo_db_UserPwdReq_aggregateGroupBy = q.F("GroupBy")
+const ___yo_db_UserPwdReq_aggregateMax = q.F("Max")
+const ___yo_db_UserPwdReq_aggregateOrderBy = q.F("OrderBy")
+const ___yo_db_UserPwdReq_aggregateQuery = q.F("Query")
+const ___yo_db_UserPwdReq_aggregateQueryFrom = q.F("QueryFrom")
 const ___yo_db_UserPwdReq_countMax = q.F("Max")
 const ___yo_db_UserPwdReq_countOrderBy = q.F("OrderBy")
 const ___yo_db_UserPwdReq_countQuery = q.F("Query")
@@ -281,11 +401,22 @@ const ___yo_db_UserPwdReq_deleteManyOrderBy = q.F("OrderBy")
 const ___yo_db_UserPwdReq_deleteManyQuery = q.F("Query")
 const ___yo_db_UserPwdReq_deleteManyQueryFrom = q.F("QueryFrom")
 const ___yo_db_UserPwdReq_deleteOneId = q.F("Id")
+const ___yo_db_UserPwdReq_exportFields = q.F("Fields")
+const ___yo_db_UserPwdReq_exportFormat = q.F("Format")
+const ___yo_db_UserPwdReq_exportMax = q.F("Max")
+const ___yo_db_UserPwdReq_exportOrderBy = q.F("OrderBy")
+const ___yo_db_UserPwdReq_exportQuery = q.F("Query")
+const ___yo_db_UserPwdReq_exportQueryFrom = q.F("QueryFrom")
 const ___yo_db_UserPwdReq_findByIdId = q.F("Id")
 const ___yo_db_UserPwdReq_findManyMax = q.F("Max")
 const ___yo_db_UserPwdReq_findManyOrderBy = q.F("OrderBy")
 const ___yo_db_UserPwdReq_findManyQuery = q.F("Query")
 const ___yo_db_UserPwdReq_findManyQueryFrom = q.F("QueryFrom")
+const ___yo_db_UserPwdReq_findManyPagedMax = q.F("Max")
+const ___yo_db_UserPwdReq_findManyPagedOrderBy = q.F("OrderBy")
+const ___yo_db_UserPwdReq_findManyPagedPageTok = q.F("PageTok")
+const ___yo_db_UserPwdReq_findManyPagedQuery = q.F("Query")
+const ___yo_db_UserPwdReq_findManyPagedQueryFrom = q.F("QueryFrom")
 const ___yo_db_UserPwdReq_findOneMax = q.F("Max")
 const ___yo_db_UserPwdReq_findOneOrderBy = q.F("OrderBy")
 const ___yo_db_UserPwdReq_findOneQuery = q.F("Query")
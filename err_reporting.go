@@ -37,6 +37,7 @@ type ErrEntry struct {
 	JobRunId    yodb.I64
 	JobTaskId   yodb.I64
 	DbTx        yodb.Bool
+	DbStats     yodb.Text
 }
 
 func init() {
@@ -62,6 +63,7 @@ func init() {
 			NumCaught:  1,
 			DbTx:       (nowInvalidCtx.Db.Tx != nil),
 			CtxVals:    yodb.Text(json_ctx_vals),
+			DbStats:    yodb.Text(nowInvalidCtx.Db.Stats.String()),
 		}
 		if nowInvalidCtx.Job != nil {
 			err_entry.JobRunId, err_entry.JobTaskId = yodb.I64(nowInvalidCtx.Job.RunId), yodb.I64(nowInvalidCtx.Job.TaskId)
@@ -96,6 +98,7 @@ func init() {
 			similar_enough.HttpUrlPath = If(err_entry.HttpUrlPath == "", similar_enough.HttpUrlPath, err_entry.HttpUrlPath)
 			similar_enough.StackTrace = If(err_entry.StackTrace == "", similar_enough.StackTrace, err_entry.StackTrace)
 			similar_enough.Err = If(err_entry.Err == "", similar_enough.Err, err_entry.Err)
+			similar_enough.DbStats = If(err_entry.DbStats == "", similar_enough.DbStats, err_entry.DbStats)
 			yodb.Update[ErrEntry](ctx, similar_enough, nil, false)
 		} // else: no-op. NumCaught==255 means "a lot, too much". dont need more, we can really stop logging any more of this...
 	}
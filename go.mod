@@ -1,6 +1,7 @@
 module yo
 
-go 1.22.3
+// 1.24 (up from 1.22.3) for `http.Server.Protocols`, used by srv/tls.go for HTTP/2 and plain-HTTP h2c without golang.org/x/net
+go 1.24
 
 require (
 	github.com/evanw/esbuild v0.19.5
@@ -2,6 +2,7 @@ package yojobs
 
 import (
 	"strconv"
+	"sync"
 	"time"
 
 	. "yo/ctx"
@@ -19,7 +20,8 @@ func init() {
 	yodb.Ensure[JobRun, JobRunField]("", nil, false,
 		yodb.Unique[JobRunField]{JobRunScheduledNextAfter},
 		yodb.AlwaysFetch[JobRunField]{JobRunVersion},
-		yodb.Index[JobRunField]{jobRunState})
+		yodb.Index[JobRunField]{jobRunState},
+		yodb.Notify{})
 	yodb.Ensure[JobTask, JobTaskField]("", nil, false,
 		yodb.AlwaysFetch[JobTaskField]{JobTaskVersion},
 		yodb.Index[JobTaskField]{jobTaskState})
@@ -34,8 +36,11 @@ const Timeout1Min = time.Minute
 type Engine interface {
 	// Resume starts the `Engine`, ie. its (from then on) regularly-recurring background workers.
 	Resume()
-	// Running returns `true` after `Resume` was called and `false` until then.
+	// Running returns `true` after `Resume` was called and `false` until then (or after `Suspend`).
 	Running() bool
+	// Suspend stops the background workers from recurring, then lets currently-running tasks finish until `drainDeadline`.
+	// Any still running then get canceled and put back to Pending (not counting as an attempt), for a later `Resume` or another instance to pick up.
+	Suspend(drainDeadline time.Time)
 	// CreateJobRun "manually schedules" an off-schedule job at the specified `dueTime`, which if missing (or set in the past) defaults to `timeNow()`.
 	CreateJobRun(ctx *Ctx, jobDef *JobDef, dueTime *yodb.DateTime) *JobRun
 	// DeleteJobRun clears from storage the specified DONE or CANCELLED `JobRun` and all its `JobTask`s, if any.
@@ -45,7 +50,7 @@ type Engine interface {
 }
 
 type Options struct {
-	// IntervalStartAndFinalizeJobs should be under 0.5 minutes.
+	// IntervalStartAndFinalizeJobs should be under 0.5 minutes. New `JobRun`s (via `yodb.Subscribe`) start it early anyway.
 	IntervalStartAndFinalizeJobs time.Duration `default:"11s"`
 	// IntervalRunTasks should be under 0.5 minutes.
 	IntervalRunTasks time.Duration `default:"11s"`
@@ -65,8 +70,16 @@ type Options struct {
 }
 
 type engine struct {
-	running bool
-	options Options
+	running     bool
+	generation  uint64 // incremented by every `Resume`, so that worker chains from before a `Suspend` don't recur alongside the new ones
+	options     Options
+	wakeUp      chan None // wakes up a waiting `startAndFinalizeJobRuns` early, on new `JobRun`s
+	suspended   chan None // closed by `Suspend`, ending the current generation's `startAndFinalizeJobRuns` right away
+	unsubscribe func()    // of the `wakeUp`-sending `yodb.Subscribe` while running
+	tasks       struct {
+		sync.Mutex
+		running map[*Ctx]bool // value: whether canceled by `Suspend`
+	}
 }
 
 func NewEngine(options Options) Engine {
@@ -86,20 +99,117 @@ func NewEngine(options Options) Engine {
 	if err != nil {
 		panic(err)
 	}
-	return &engine{options: options}
+	ret := &engine{options: options, wakeUp: make(chan None, 1)}
+	ret.tasks.running = map[*Ctx]bool{}
+	return ret
+}
+
+func (me *engine) Running() bool {
+	me.tasks.Lock()
+	defer me.tasks.Unlock()
+	return me.running
 }
 
-func (me *engine) Running() bool { return me.running }
 func (me *engine) Resume() {
-	if me.running {
+	me.tasks.Lock()
+	was_running := me.running
+	if !was_running {
+		me.running, me.generation, me.suspended = true, me.generation+1, make(chan None)
+		me.unsubscribe = yodb.Subscribe[JobRun]([]yodb.ChangeOp{yodb.ChangeInsert}, jobRunState.Equal(Pending), func(*yodb.Change[JobRun]) {
+			select {
+			case me.wakeUp <- None{}:
+			default: // already woken up
+			}
+		})
+	}
+	gen, suspended := me.generation, me.suspended
+	me.tasks.Unlock()
+	if was_running {
 		return
 	}
-	me.running = true
-	DoAfter(1*time.Second, me.startAndFinalizeJobRuns)
-	DoAfter(2*time.Second, me.runJobTasks)
-	DoAfter(3*time.Second, me.ensureJobRunSchedules)
-	DoAfter(4*time.Second, me.expireOrRetryDeadJobTasks)
-	DoAfter(5*time.Second, me.deleteStorageExpiredJobRuns)
+	me.doAfter(gen, 1*time.Second, func(gen uint64) { me.startAndFinalizeJobRuns(gen, suspended) })
+	me.doAfter(gen, 2*time.Second, me.runJobTasks)
+	me.doAfter(gen, 3*time.Second, me.ensureJobRunSchedules)
+	me.doAfter(gen, 4*time.Second, me.expireOrRetryDeadJobTasks)
+	me.doAfter(gen, 5*time.Second, me.deleteStorageExpiredJobRuns)
+}
+
+func (me *engine) Suspend(drainDeadline time.Time) {
+	me.tasks.Lock()
+	me.running = false
+	if me.unsubscribe != nil {
+		me.unsubscribe()
+		me.unsubscribe = nil
+	}
+	if me.suspended != nil {
+		close(me.suspended)
+		me.suspended = nil
+	}
+	me.tasks.Unlock()
+
+	me.tasksWait(drainDeadline)
+	me.tasks.Lock()
+	for ctx := range me.tasks.running {
+		me.tasks.running[ctx] = true
+		ctx.Cancel()
+	}
+	me.tasks.Unlock()
+	// the canceled tasks still get to store their going-back-to-Pending. any outlasting this are left for `expireOrRetryDeadJobTasks`
+	me.tasksWait(time.Now().Add(time.Second))
+}
+
+func (me *engine) tasksWait(until time.Time) {
+	for time.Now().Before(until) {
+		me.tasks.Lock()
+		num_running := len(me.tasks.running)
+		me.tasks.Unlock()
+		if num_running == 0 {
+			return
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// runningGen returns whether `gen` is the `generation` of the current (not `Suspend`ed) `Resume`.
+func (me *engine) runningGen(gen uint64) bool {
+	me.tasks.Lock()
+	defer me.tasks.Unlock()
+	return me.running && (me.generation == gen)
+}
+
+// doAfter is `DoAfter` for the recurring workers of `Resume` generation `gen`, which stop recurring
+// on `Suspend` (even if `Resume`d again before `interval` is over, as that starts a new generation).
+func (me *engine) doAfter(gen uint64, interval time.Duration, do func(gen uint64)) {
+	if me.runningGen(gen) {
+		DoAfter(interval, func() {
+			if me.runningGen(gen) {
+				do(gen)
+			}
+		})
+	}
+}
+
+// taskRunningAdd tracks `ctx` for `Suspend` if still `running`, otherwise the task should not start.
+func (me *engine) taskRunningAdd(ctx *Ctx) bool {
+	me.tasks.Lock()
+	defer me.tasks.Unlock()
+	if me.running {
+		me.tasks.running[ctx] = false
+	}
+	return me.running
+}
+
+func (me *engine) taskRunningDel(ctx *Ctx) {
+	me.tasks.Lock()
+	defer me.tasks.Unlock()
+	delete(me.tasks.running, ctx)
+}
+
+// taskSuspended returns whether the task running with `ctx` was canceled by `Suspend`.
+func (me *engine) taskSuspended(ctx *Ctx) bool {
+	me.tasks.Lock()
+	defer me.tasks.Unlock()
+	return me.tasks.running[ctx]
 }
 
 func (me *engine) cancelJobRuns(ctx *Ctx, jobRunsToCancel map[CancellationReason][]*JobRun) {
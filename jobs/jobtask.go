@@ -10,6 +10,7 @@ import (
 	yojson "yo/json"
 	. "yo/util"
 	"yo/util/kv"
+	"yo/util/sl"
 )
 
 type JobTask struct {
@@ -43,7 +44,7 @@ func (me *JobTask) Succeeded() bool {
 
 func (me *JobTask) markForRetryOrAsFailed(ctx *Ctx) (didMarkForRetry bool) {
 	job_def := me.jobDef(ctx)
-	if (job_def != nil) && (len(me.Attempts) <= int(job_def.MaxTaskRetries)) { // `<=` because first attempt was not a RE-try
+	if (job_def != nil) && (len(me.attemptsNotInterrupted()) <= int(job_def.MaxTaskRetries)) { // `<=` because first attempt was not a RE-try
 		me.state, me.StartTime, me.FinishTime = yodb.Text(Pending), nil, nil
 		return true
 	}
@@ -130,6 +131,16 @@ func taskAttempt() TaskAttempt {
 	return TaskAttempt{T: t.Format(time.RFC3339), t: t}
 }
 
+// attemptsNotInterrupted are the `Attempts` other than those cut short by `Engine.Suspend`, which don't count as retries.
+func (me *JobTask) attemptsNotInterrupted() []TaskAttempt {
+	return sl.Where(me.Attempts, func(it TaskAttempt) bool { return !it.Interrupted() })
+}
+
+// Interrupted is whether the attempt was cut short by `Engine.Suspend` (so the task went back to `Pending` to be run again).
+func (me *TaskAttempt) Interrupted() bool {
+	return (me.Err != nil) && (me.Err.Error() == errTaskInterrupted.Error()) // not `errors.Is`: `Err`s come back from the DB as mere strings
+}
+
 func (me *TaskAttempt) Time() *time.Time {
 	if (me.t == nil) && (me.T != "") {
 		if t, err := time.Parse(time.RFC3339, me.T); err == nil {
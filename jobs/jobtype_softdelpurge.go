@@ -0,0 +1,42 @@
+package yojobs
+
+import (
+	. "yo/ctx"
+	yodb "yo/db"
+	. "yo/util"
+	"yo/util/sl"
+)
+
+var softDelPurgeJobTypeId = Register[softDelPurgeJob, None, None, softDelPurgeTaskDetails, softDelPurgeTaskResults](func(string) softDelPurgeJob {
+	return softDelPurgeJob{}
+})
+
+// SoftDelPurgeJobDef hard-deletes soft-deleted rows of all `yodb.SoftDelete` tables with a `PurgeAfterDays` > 0.
+var SoftDelPurgeJobDef = JobDef{
+	Name:                             yodb.Text(softDelPurgeJobTypeId),
+	JobTypeId:                        yodb.Text(softDelPurgeJobTypeId),
+	Schedules:                        ScheduleOncePerDay,
+	TimeoutSecsTaskRun:               123,
+	TimeoutSecsJobRunPrepAndFinalize: 11,
+	MaxTaskRetries:                   2,
+	DeleteAfterDays:                  3,
+}
+
+type softDelPurgeJob None
+type softDelPurgeTaskDetails struct{ TableName string }
+type softDelPurgeTaskResults struct{ NumDeleted int64 }
+
+func (softDelPurgeJob) JobDetails(_ *Ctx) JobDetails { return nil }
+
+func (softDelPurgeJob) JobResults(_ *Ctx) (func(func() *Ctx, *JobTask, *bool), func() JobResults) {
+	return nil, nil
+}
+
+func (softDelPurgeJob) TaskDetails(_ *Ctx, stream func([]TaskDetails)) {
+	stream(sl.As(yodb.SoftDeletePurgeableTables(),
+		func(it string) TaskDetails { return &softDelPurgeTaskDetails{TableName: it} }))
+}
+
+func (softDelPurgeJob) TaskResults(ctx *Ctx, task TaskDetails) TaskResults {
+	return &softDelPurgeTaskResults{NumDeleted: yodb.PurgeSoftDeleted(ctx, task.(*softDelPurgeTaskDetails).TableName)}
+}
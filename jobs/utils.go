@@ -16,6 +16,8 @@ func qById(id yodb.I64) q.Query {
 	return yodb.ColID.Equal(id)
 }
 
+var errTaskInterrupted = errors.New("task interrupted by engine suspension, to be run again")
+
 func errNotFoundJobRun(id yodb.I64) error {
 	return errors.New(str.Fmt("job run '%d' no longer exists", id))
 }
@@ -17,16 +17,24 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 )
 
-func (me *engine) startAndFinalizeJobRuns() {
-	defer DoAfter(me.options.IntervalStartAndFinalizeJobs, me.startAndFinalizeJobRuns)
-
-	me.finalizeDoneJobRuns()
-	me.startDueJobRuns()
-	me.finalizeCancellingJobRuns()
+// startAndFinalizeJobRuns is the single waiter of `Resume` generation `gen`: it recurs every `IntervalStartAndFinalizeJobs`
+// (or early on `wakeUp`) in place, until `suspended` is closed by `Suspend`.
+func (me *engine) startAndFinalizeJobRuns(gen uint64, suspended <-chan None) {
+	for me.runningGen(gen) {
+		me.finalizeDoneJobRuns()
+		me.startDueJobRuns()
+		me.finalizeCancellingJobRuns()
+
+		select {
+		case <-time.After(me.options.IntervalStartAndFinalizeJobs):
+		case <-me.wakeUp:
+		case <-suspended:
+		}
+	}
 }
 
 func (me *engine) finalizeDoneJobRuns() {
-	ctx := NewCtxNonHttp(Timeout1Min, false, "")
+	ctx := engineCtx()
 	defer ctx.OnDone(nil)
 	job_runs := yodb.FindMany[JobRun](ctx, jobRunState.Equal(Running), 0, nil)
 
@@ -96,7 +104,7 @@ func (me *engine) finalizeDoneJobRun(ctxForCacheReuse *Ctx, jobRun *JobRun) {
 }
 
 func (me *engine) finalizeCancellingJobRuns() {
-	ctx := NewCtxNonHttp(Timeout1Min, false, "")
+	ctx := engineCtx()
 	defer ctx.OnDone(nil)
 
 	// no db-tx(s) wanted here by design, as many task cancellations as we can get are fine for us, running on an interval anyway
@@ -109,7 +117,7 @@ func (me *engine) finalizeCancellingJobRuns() {
 }
 
 func (me *engine) startDueJobRuns() {
-	ctx := NewCtxNonHttp(Timeout1Min, false, "")
+	ctx := engineCtx()
 	defer ctx.OnDone(nil)
 
 	jobs_due := yodb.FindMany[JobRun](ctx, jobRunState.Equal(Pending).And(JobRunDueTime.LessThan(time.Now())), 0, nil, JobRunDueTime.Asc())
@@ -190,10 +198,10 @@ func (me *engine) startDueJob(ctxForCacheReuse *Ctx, jobRun *JobRun, jobDef *Job
 	} // else: just stays pending. eventually, it produces tasks. if never, it just remains with no need to cancel, re-schedule, cancel, re-schedule, etc.
 }
 
-func (me *engine) ensureJobRunSchedules() {
-	ctx := NewCtxNonHttp(Timeout1Min, false, "")
+func (me *engine) ensureJobRunSchedules(gen uint64) {
+	ctx := engineCtx()
 	defer ctx.OnDone(func() {
-		DoAfter(me.options.IntervalEnsureJobSchedules, me.ensureJobRunSchedules)
+		me.doAfter(gen, me.options.IntervalEnsureJobSchedules, me.ensureJobRunSchedules)
 	})
 
 	cancel_jobs := map[CancellationReason][]*JobRun{}
@@ -244,10 +252,10 @@ func (me *engine) scheduleJobRun(ctx *Ctx, jobDef *JobDef, jobRunPrev *JobRun) *
 	return nil
 }
 
-func (me *engine) deleteStorageExpiredJobRuns() {
-	ctx := NewCtxNonHttp(Timeout1Min, false, "")
+func (me *engine) deleteStorageExpiredJobRuns(gen uint64) {
+	ctx := engineCtx()
 	defer ctx.OnDone(func() {
-		DoAfter(me.options.IntervalDeleteStorageExpiredJobs, me.deleteStorageExpiredJobRuns)
+		me.doAfter(gen, me.options.IntervalDeleteStorageExpiredJobs, me.deleteStorageExpiredJobRuns)
 	})
 
 	job_defs := yodb.FindMany[JobDef](ctx, JobDefDeleteAfterDays.GreaterThan(0), 0, nil /* keep it all-fields due to JobDef.OnAfterLoaded */)
@@ -261,10 +269,10 @@ func (me *engine) deleteStorageExpiredJobRuns() {
 
 // A died task is one whose runner died between its start and its finishing or orderly timeout.
 // It's found in the DB as still RUNNING despite its timeout moment being over a minute ago:
-func (me *engine) expireOrRetryDeadJobTasks() {
-	ctx := NewCtxNonHttp(Timeout1Min, false, "")
+func (me *engine) expireOrRetryDeadJobTasks(gen uint64) {
+	ctx := engineCtx()
 	defer ctx.OnDone(func() {
-		DoAfter(me.options.IntervalExpireOrRetryDeadTasks, me.expireOrRetryDeadJobTasks)
+		me.doAfter(gen, me.options.IntervalExpireOrRetryDeadTasks, me.expireOrRetryDeadJobTasks)
 	})
 
 	jobs := sl.Grouped(
@@ -318,10 +326,10 @@ func (me *engine) expireOrRetryDeadJobTasksForJobDef(ctx *Ctx, jobDef *JobDef, r
 	}
 }
 
-func (me *engine) runJobTasks() {
-	ctx := NewCtxNonHttp(Timeout1Min, false, "")
+func (me *engine) runJobTasks(gen uint64) {
+	ctx := engineCtx()
 	defer ctx.OnDone(func() {
-		DoAfter(me.options.IntervalRunTasks, me.runJobTasks)
+		me.doAfter(gen, me.options.IntervalRunTasks, me.runJobTasks)
 	})
 
 	pending_tasks := yodb.FindMany[JobTask](ctx, jobTaskState.Equal(string(Pending)), me.options.FetchTasksToRun, nil)
@@ -339,6 +347,10 @@ func (me *engine) runTask(ctxForCacheReuse *Ctx, task *JobTask) {
 	}
 	ctx := ctxForCacheReuse.CopyButWith(timeout, true)
 	defer ctx.OnDone(nil)
+	if !me.taskRunningAdd(ctx) {
+		return // `Suspend`ed meanwhile: leave it Pending
+	}
+	defer me.taskRunningDel(ctx)
 
 	// first, attempt to reserve task for running vs. other pods
 	already_canceled := (job_run == nil) || (job_run.State() == Cancelled) || (job_run.State() == JobRunCancelling) ||
@@ -378,6 +390,18 @@ func (me *engine) runTask(ctxForCacheReuse *Ctx, task *JobTask) {
 		})
 	}
 
+	if (!already_canceled) && me.taskSuspended(ctx) {
+		// back to Pending, with the attempt kept but marked interrupted (not counting as a retry), since it was cut short by us, not by the task or its timeout
+		ctx_store := engineCtx()
+		defer ctx_store.OnDone(nil)
+		task.state, task.FinishTime, task.Attempts[0].Err = yodb.Text(Pending), nil, errTaskInterrupted
+		if len(task.attemptsNotInterrupted()) == 0 {
+			task.StartTime = nil
+		}
+		yodb.Update[JobTask](ctx_store, task, nil, false, JobTaskFields(jobTaskState, JobTaskStartTime, JobTaskFinishTime, JobTaskAttempts)...)
+		return
+	}
+
 	task.state, task.FinishTime =
 		yodb.Text(If(already_canceled, Cancelled, Done)), yodb.DtNow()
 	err_ctx := ctx.Err()
@@ -400,3 +424,10 @@ func (me *engine) runTask(ctxForCacheReuse *Ctx, task *JobTask) {
 	task_upd_fields = sl.Without(task_upd_fields, JobTaskStartTime.F())
 	yodb.Update[JobTask](ctx, task, nil, false, task_upd_fields...)
 }
+
+// engineCtx is for the engine's own bookkeeping, which must never see stale reads from `YO_DB_CONN_URL_READONLY` replicas.
+func engineCtx() *Ctx {
+	ctx := NewCtxNonHttp(Timeout1Min, false, "")
+	ctx.Db.ReadYourWrites = true
+	return ctx
+}
@@ -37,6 +37,9 @@ func (me JobDefField) GreaterOrEqual(a1 interface{}) q.Query { return ((q.F)(me)
 func (me JobDefField) GreaterThan(a1 interface{}) q.Query    { return ((q.F)(me)).GreaterThan(a1) }
 func (me JobDefField) In(a1 ...interface{}) q.Query          { return ((q.F)(me)).In(a1...) }
 func (me JobDefField) InArr(a1 interface{}) q.Query          { return ((q.F)(me)).InArr(a1) }
+func (me JobDefField) JsonContains(a1 interface{}) q.Query   { return ((q.F)(me)).JsonContains(a1) }
+func (me JobDefField) JsonHasKey(a1 string) q.Query          { return ((q.F)(me)).JsonHasKey(a1) }
+func (me JobDefField) JsonPath(a1 ...interface{}) q.Operand  { return ((q.F)(me)).JsonPath(a1...) }
 func (me JobDefField) LessOrEqual(a1 interface{}) q.Query    { return ((q.F)(me)).LessOrEqual(a1) }
 func (me JobDefField) LessThan(a1 interface{}) q.Query       { return ((q.F)(me)).LessThan(a1) }
 func (me JobDefField) Not() q.Query                          { return ((q.F)(me)).Not() }
@@ -111,6 +114,9 @@ func (me JobRunField) GreaterOrEqual(a1 interface{}) q.Query { return ((q.F)(me)
 func (me JobRunField) GreaterThan(a1 interface{}) q.Query    { return ((q.F)(me)).GreaterThan(a1) }
 func (me JobRunField) In(a1 ...interface{}) q.Query          { return ((q.F)(me)).In(a1...) }
 func (me JobRunField) InArr(a1 interface{}) q.Query          { return ((q.F)(me)).InArr(a1) }
+func (me JobRunField) JsonContains(a1 interface{}) q.Query   { return ((q.F)(me)).JsonContains(a1) }
+func (me JobRunField) JsonHasKey(a1 string) q.Query          { return ((q.F)(me)).JsonHasKey(a1) }
+func (me JobRunField) JsonPath(a1 ...interface{}) q.Operand  { return ((q.F)(me)).JsonPath(a1...) }
 func (me JobRunField) LessOrEqual(a1 interface{}) q.Query    { return ((q.F)(me)).LessOrEqual(a1) }
 func (me JobRunField) LessThan(a1 interface{}) q.Query       { return ((q.F)(me)).LessThan(a1) }
 func (me JobRunField) Not() q.Query                          { return ((q.F)(me)).Not() }
@@ -167,6 +173,9 @@ func (me JobTaskField) GreaterOrEqual(a1 interface{}) q.Query { return ((q.F)(me
 func (me JobTaskField) GreaterThan(a1 interface{}) q.Query    { return ((q.F)(me)).GreaterThan(a1) }
 func (me JobTaskField) In(a1 ...interface{}) q.Query          { return ((q.F)(me)).In(a1...) }
 func (me JobTaskField) InArr(a1 interface{}) q.Query          { return ((q.F)(me)).InArr(a1) }
+func (me JobTaskField) JsonContains(a1 interface{}) q.Query   { return ((q.F)(me)).JsonContains(a1) }
+func (me JobTaskField) JsonHasKey(a1 string) q.Query          { return ((q.F)(me)).JsonHasKey(a1) }
+func (me JobTaskField) JsonPath(a1 ...interface{}) q.Operand  { return ((q.F)(me)).JsonPath(a1...) }
 func (me JobTaskField) LessOrEqual(a1 interface{}) q.Query    { return ((q.F)(me)).LessOrEqual(a1) }
 func (me JobTaskField) LessThan(a1 interface{}) q.Query       { return ((q.F)(me)).LessThan(a1) }
 func (me JobTaskField) Not() q.Query                          { return ((q.F)(me)).Not() }
@@ -9,6 +9,7 @@ import (
 )
 
 type Num = json.Number
+type Raw = json.RawMessage
 
 var (
 	Unmarshal   = json.Unmarshal
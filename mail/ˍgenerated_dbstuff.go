@@ -31,6 +31,9 @@ func (me MailReqField) GreaterOrEqual(a1 interface{}) q.Query { return ((q.F)(me
 func (me MailReqField) GreaterThan(a1 interface{}) q.Query    { return ((q.F)(me)).GreaterThan(a1) }
 func (me MailReqField) In(a1 ...interface{}) q.Query          { return ((q.F)(me)).In(a1...) }
 func (me MailReqField) InArr(a1 interface{}) q.Query          { return ((q.F)(me)).InArr(a1) }
+func (me MailReqField) JsonContains(a1 interface{}) q.Query   { return ((q.F)(me)).JsonContains(a1) }
+func (me MailReqField) JsonHasKey(a1 string) q.Query          { return ((q.F)(me)).JsonHasKey(a1) }
+func (me MailReqField) JsonPath(a1 ...interface{}) q.Operand  { return ((q.F)(me)).JsonPath(a1...) }
 func (me MailReqField) LessOrEqual(a1 interface{}) q.Query    { return ((q.F)(me)).LessOrEqual(a1) }
 func (me MailReqField) LessThan(a1 interface{}) q.Query       { return ((q.F)(me)).LessThan(a1) }
 func (me MailReqField) Not() q.Query                          { return ((q.F)(me)).Not() }
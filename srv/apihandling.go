@@ -22,6 +22,7 @@ const (
 	ErrUnauthorized              Err = "Unauthorized"
 	ErrUnacceptableContentLength Err = "UnacceptableContentLength"
 	ErrUnacceptableContentType   Err = "UnacceptableContentType"
+	ErrTooManyRequests           Err = "TooManyRequests"
 	yoAdminApisUrlPrefix             = "__/yo/"
 	apisContentType_Json             = "application/json"
 	apisContentType_Multipart        = "multipart/form-data"
@@ -33,7 +34,8 @@ var (
 		"": {ErrTimedOut, ErrUnacceptableContentLength, ErrUnacceptableContentType},
 	}
 	ErrsNoPrefix  = errsNoCodegen
-	errsNoCodegen = []Err{ErrTimedOut, ErrUnacceptableContentLength, ErrUnacceptableContentType, ErrUnauthorized, ErrDbUpdExpectedIdGt0, ErrMustBeAdmin}
+	ErrsOwnPkg    = map[Err]string{} // `Err`s to codegen into the named package rather than into those of the API methods failing with them
+	errsNoCodegen = []Err{ErrTimedOut, ErrUnacceptableContentLength, ErrUnacceptableContentType, ErrUnauthorized, ErrDbUpdExpectedIdGt0, ErrMustBeAdmin, ErrTooManyRequests}
 
 	// requests to key+'/' will be served from the corresponding FS
 	apisStdRespHeaders = str.Dict{
@@ -65,12 +67,14 @@ type ApiMethod interface {
 	methodPath(bool) string
 	methodNameUp0() string
 	isMultipartForm() bool
+	rateLimiters() []*rateLimiter
 	IsMultipartForm() ApiMethod
 	From(ApiPkgInfo) ApiMethod
 	KnownErrs(isForCodegenGo bool) []Err
 	Checks(...Fails) ApiMethod
 	CouldFailWith(...Err) ApiMethod
 	FailIf(func(*Ctx) bool, Err) ApiMethod
+	RateLimited(...RateLimit) ApiMethod
 }
 
 type ApiCtx[TIn any, TOut any] struct {
@@ -116,13 +120,15 @@ type apiMethod[TIn any, TOut any] struct {
 	failIfs       []Fails
 	preChecks     []Pair[Err, func(*Ctx) bool]
 	multipartForm bool
+	rateLimits    []*rateLimiter
 	PkgInfo       ApiPkgInfo
 }
 
-func (me *apiMethod[TIn, TOut]) pkgInfo() ApiPkgInfo    { return me.PkgInfo }
-func (me *apiMethod[TIn, TOut]) failsIf() []Fails       { return me.failIfs }
-func (me *apiMethod[TIn, TOut]) handler() apiHandleFunc { return me.handleFunc }
-func (me *apiMethod[TIn, TOut]) isMultipartForm() bool  { return me.multipartForm }
+func (me *apiMethod[TIn, TOut]) pkgInfo() ApiPkgInfo          { return me.PkgInfo }
+func (me *apiMethod[TIn, TOut]) failsIf() []Fails             { return me.failIfs }
+func (me *apiMethod[TIn, TOut]) handler() apiHandleFunc       { return me.handleFunc }
+func (me *apiMethod[TIn, TOut]) isMultipartForm() bool        { return me.multipartForm }
+func (me *apiMethod[TIn, TOut]) rateLimiters() []*rateLimiter { return me.rateLimits }
 func (me *apiMethod[TIn, TOut]) IsMultipartForm() ApiMethod {
 	me.multipartForm = true
 	return me
@@ -150,6 +156,13 @@ func (me *apiMethod[TIn, TOut]) FailIf(inCaseOf func(*Ctx) bool, withErr Err) Ap
 	return me
 }
 
+// RateLimited overrides for this method any default `RateLimit`s of `RateLimiting` middlewares, and applies even without any such.
+func (me *apiMethod[TIn, TOut]) RateLimited(rateLimits ...RateLimit) ApiMethod {
+	me.CouldFailWith(ErrTooManyRequests)
+	me.rateLimits = append(me.rateLimits, newRateLimiters(rateLimits)...)
+	return me
+}
+
 func (me *apiMethod[TIn, TOut]) PkgName() string {
 	if me.PkgInfo != nil {
 		return me.PkgInfo.PkgName()
@@ -277,6 +290,7 @@ func apiHandleRequest(ctx *Ctx) (result any, handlerCalled bool) {
 		ctx.HttpErr(404, "Not Found")
 		return
 	}
+	rateLimitCheck(ctx, api_method.rateLimiters())
 
 	max_payload_size := (1024 * 1024 * int64(If(!api_method.isMultipartForm(), Cfg.YO_API_MAX_REQ_CONTENTLENGTH_MB, Cfg.YO_API_MAX_REQ_MULTIPART_LENGTH_MB)))
 	if (ctx.Http.Req.ContentLength < 0) || (ctx.Http.Req.ContentLength > max_payload_size) {
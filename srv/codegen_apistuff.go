@@ -139,14 +139,28 @@ func codegenGo(apiRefl *apiReflect) {
 			}
 		}
 
-		if !pkgsImportingSrv[pkg_name] {
-			FsDelFile(out_file_path)
-			continue
+		var pkg_errs_own []Err
+		for err, err_pkg_name := range ErrsOwnPkg {
+			if err_pkg_name == pkg_name {
+				pkg_errs_own = append(pkg_errs_own, err)
+			}
 		}
 
 		var buf str.Buf
 		buf.WriteString(codegenEmitTopCommentLine)
 		buf.WriteString("package " + pkg_name + "\n")
+		if !pkgsImportingSrv[pkg_name] {
+			if len(pkg_errs_own) == 0 {
+				FsDelFile(out_file_path)
+				continue
+			}
+			buf.WriteString("import util \"yo/util\"\n")
+			for _, err := range sl.Sorted(pkg_errs_own) {
+				buf.WriteString("const Err" + string(err) + " util.Err = \"" + string(err) + "\"\n")
+			}
+			codegenGoWrite(out_file_path, &buf, &did_write_files)
+			continue
+		}
 		buf.WriteString("import reflect \"reflect\"\n")
 		buf.WriteString("import yosrv \"yo/srv\"\n")
 		buf.WriteString("import util \"yo/util\"\n")
@@ -163,6 +177,11 @@ func codegenGo(apiRefl *apiReflect) {
 		for _, err := range errsNoCodegen {
 			err_emitted[err] = true
 		}
+		for err, err_pkg_name := range ErrsOwnPkg {
+			if _, found := pkgsFound[err_pkg_name]; found && (err_pkg_name != pkg_name) {
+				err_emitted[err] = true
+			}
+		}
 		for _, method_path := range sl.Sorted(kv.Keys(pkg_methods)) {
 			for _, err := range sl.Sorted(kv.Keys(apiRefl.KnownErrs[method_path])) {
 				if !err_emitted[err] {
@@ -171,6 +190,12 @@ func codegenGo(apiRefl *apiReflect) {
 				}
 			}
 		}
+		for _, err := range sl.Sorted(pkg_errs_own) {
+			if !err_emitted[err] {
+				err_emitted[err] = true
+				buf.WriteString("const Err" + string(err) + " util.Err = \"" + string(err) + "\"\n")
+			}
+		}
 
 		var do_fields func(str.Dict, string, string)
 		do_fields = func(typeRefl str.Dict, namePrefix string, fieldStrPrefix string) {
@@ -199,21 +224,25 @@ func codegenGo(apiRefl *apiReflect) {
 			do_fields(input_type, name_prefix, "")
 		}
 
-		src_raw, err := format.Source([]byte(buf.String()))
-		if err != nil {
-			panic(err)
-		}
-
-		if src_old := FsRead(out_file_path); !bytes.Equal(src_old, src_raw) {
-			FsWrite(out_file_path, src_raw)
-			did_write_files = append(did_write_files, str.TrimPref(filepath.Dir(out_file_path), os.Getenv("GOPATH")+"/"))
-		}
+		codegenGoWrite(out_file_path, &buf, &did_write_files)
 	}
 	if len(did_write_files) > 0 {
 		panic("apicodegen'd, please restart (" + str.Join(did_write_files, ", ") + ")")
 	}
 }
 
+func codegenGoWrite(outFilePath string, buf *str.Buf, didWriteFiles *[]string) {
+	src_raw, err := format.Source([]byte(buf.String()))
+	if err != nil {
+		panic(err)
+	}
+
+	if src_old := FsRead(outFilePath); !bytes.Equal(src_old, src_raw) {
+		FsWrite(outFilePath, src_raw)
+		*didWriteFiles = append(*didWriteFiles, str.TrimPref(filepath.Dir(outFilePath), os.Getenv("GOPATH")+"/"))
+	}
+}
+
 func codegenOpenApi(apiRefl *apiReflect) (didFsWrites []string) {
 	out_file_path := curMainStaticDirPath_App + "/openapi.json"
 
@@ -237,6 +266,7 @@ func codegenOpenApi(apiRefl *apiReflect) (didFsWrites []string) {
 	}
 	openapi.Components.Headers = map[string]yopenapi.Header{
 		yoctx.HttpResponseHeaderName_UserEmailAddr: {Descr: "empty if not authenticated, else current `User`'s `Account`-identifying `EmailAddr`", Content: map[string]yopenapi.Media{yoctx.MimeTypePlainText: {Example: "user123@foo.bar"}}},
+		"Retry-After": {Descr: "with `" + string(ErrTooManyRequests) + "`: the number of seconds after which a retry will not be rate-limited", Content: map[string]yopenapi.Media{yoctx.MimeTypePlainText: {Example: "3"}}},
 	}
 	for header_name, header_value := range apisStdRespHeaders {
 		if ctype := "Content-Type"; header_name != ctype {
@@ -307,6 +337,9 @@ func codegenOpenApi(apiRefl *apiReflect) (didFsWrites []string) {
 				Content: map[string]yopenapi.Media{yoctx.MimeTypePlainText: {Examples: kv.FromKeys(str_errs, func(it string) yopenapi.Example { return yopenapi.Example{Value: it} })}},
 				Headers: map[string]yopenapi.CanHaveRef{},
 			}
+			if sl.Has(errs, ErrTooManyRequests) {
+				path.Post.Responses[http_status_code].Headers["Retry-After"] = yopenapi.CanHaveRef{Ref: yopenapi.RefHeader("Retry-After")}
+			}
 		}
 		for http_status_code := range path.Post.Responses {
 			resp := path.Post.Responses[http_status_code]
@@ -0,0 +1,133 @@
+package yosrv
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/fs"
+	"net/http"
+	"sync"
+	"time"
+
+	. "yo/cfg"
+	yoctx "yo/ctx"
+	"yo/util/str"
+)
+
+const apiRespGzipMinBytesDefault = 1024
+
+// StaticFileSidecarEncodings are the `Content-Encoding`s (in order of preference) of precompressed static-file
+// sidecars served instead of the original (if present as its same-dir neighbour with the `.br` or `.gz` extension added).
+var StaticFileSidecarEncodings = []struct{ Encoding, FileExt string }{{"br", ".br"}, {"gzip", ".gz"}}
+
+var staticFileETags sync.Map // staticFileETagKey => string
+
+type staticFileETagKey struct {
+	fsName   string // "" for `StaticFileDir_Yo` and `StaticFileDir_App`, whose file names differ by their dir prefix
+	fileName string
+	size     int64
+	modTime  time.Time
+}
+
+// httpAcceptsEncoding returns whether the `Accept-Encoding` request header lists `encoding` (other than with `q=0`).
+func httpAcceptsEncoding(req *http.Request, encoding string) bool {
+	for _, accept := range str.Split(req.Header.Get("Accept-Encoding"), ",") {
+		name, params, _ := str.Cut(str.Trim(accept), ";")
+		if str.Lo(str.Trim(name)) == encoding {
+			params = str.Trim(params)
+			return (!str.Begins(params, "q=")) || (str.Replace(str.TrimPref(params, "q="), str.Dict{"0": "", ".": ""}) != "")
+		}
+	}
+	return false
+}
+
+// Gzipped returns `data` gzip-compressed at `level` (see `compress/gzip`).
+func Gzipped(data []byte, level int) []byte {
+	var buf bytes.Buffer
+	gz, err := gzip.NewWriterLevel(&buf, level)
+	if err != nil {
+		panic(err)
+	}
+	if _, err = gz.Write(data); err != nil {
+		panic(err)
+	}
+	if err = gz.Close(); err != nil {
+		panic(err)
+	}
+	return buf.Bytes()
+}
+
+// apiRespMaybeGzip gzips API JSON responses of at least `YO_API_RESP_GZIP_MIN_BYTES` for clients accepting it.
+// (No brotli here: there's no encoder for it in the standard library. Static files can have `.br` sidecars.)
+func apiRespMaybeGzip(ctx *yoctx.Ctx, respData []byte) []byte {
+	min_bytes := Cfg.YO_API_RESP_GZIP_MIN_BYTES
+	if min_bytes == 0 {
+		min_bytes = apiRespGzipMinBytesDefault
+	}
+	if (min_bytes < 0) || (len(respData) < min_bytes) {
+		return respData
+	}
+	ctx.Http.Resp.Header().Add("Vary", "Accept-Encoding")
+	if !httpAcceptsEncoding(ctx.Http.Req, "gzip") {
+		return respData
+	}
+	ctx.Timings.Step("gzip resp")
+	ctx.Http.Resp.Header().Set("Content-Encoding", "gzip")
+	return Gzipped(respData, gzip.BestSpeed)
+}
+
+// httpServeStaticFile serves the `fileName` in `fsys` (or its best-accepted `StaticFileSidecarEncodings` sidecar) with a
+// strong `ETag` for conditional requests. Returns `false` for dirs and non-existing files, which are for `http.FileServer`.
+func httpServeStaticFile(ctx *yoctx.Ctx, fsName string, fsys fs.FS, fileName string) bool {
+	file_info, err := fs.Stat(fsys, fileName)
+	if (err != nil) || file_info.IsDir() {
+		return false
+	}
+	serve_file_name, resp_headers, has_sidecars := fileName, ctx.Http.Resp.Header(), false
+	for _, sidecar := range StaticFileSidecarEncodings {
+		if sidecar_info, err := fs.Stat(fsys, fileName+sidecar.FileExt); (err == nil) && !sidecar_info.IsDir() {
+			has_sidecars = true
+			if httpAcceptsEncoding(ctx.Http.Req, sidecar.Encoding) {
+				serve_file_name, file_info = fileName+sidecar.FileExt, sidecar_info
+				resp_headers.Set("Content-Encoding", sidecar.Encoding)
+				break
+			}
+		}
+	}
+	if has_sidecars {
+		resp_headers.Add("Vary", "Accept-Encoding")
+	}
+
+	file, err := fsys.Open(serve_file_name)
+	if err != nil {
+		panic(err)
+	}
+	defer file.Close()
+	content, _ := file.(io.ReadSeeker)
+	if content == nil {
+		data, err := io.ReadAll(file)
+		if err != nil {
+			panic(err)
+		}
+		content = bytes.NewReader(data)
+	}
+
+	etag_key := staticFileETagKey{fsName: fsName, fileName: serve_file_name, size: file_info.Size(), modTime: file_info.ModTime()}
+	etag, _ := staticFileETags.Load(etag_key)
+	if etag == nil {
+		hash := sha256.New()
+		if _, err = io.Copy(hash, content); err != nil {
+			panic(err)
+		}
+		if _, err = content.Seek(0, io.SeekStart); err != nil {
+			panic(err)
+		}
+		etag = str.Q(hex.EncodeToString(hash.Sum(nil)[:16]))
+		staticFileETags.Store(etag_key, etag)
+	}
+	resp_headers.Set("ETag", etag.(string))
+	http.ServeContent(ctx.Http.Resp, ctx.Http.Req, fileName /* not `serve_file_name`, for the `Content-Type` */, file_info.ModTime(), content)
+	return true
+}
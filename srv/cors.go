@@ -0,0 +1,72 @@
+package yosrv
+
+import (
+	"net/http"
+
+	. "yo/cfg"
+	yoctx "yo/ctx"
+	. "yo/util"
+	"yo/util/sl"
+	"yo/util/str"
+)
+
+var corsExposeHeaders = []string{yoctx.HttpResponseHeaderName_UserEmailAddr, "Retry-After"}
+
+func corsAllowOrigins() []string {
+	return If(len(Cfg.YO_API_CORS_ALLOW_ORIGINS) == 0, []string{"*"}, Cfg.YO_API_CORS_ALLOW_ORIGINS)
+}
+
+func corsCheckCfg() {
+	if Cfg.YO_API_CORS_ALLOW_CREDENTIALS && sl.Has(corsAllowOrigins(), "*") {
+		panic("YO_API_CORS_ALLOW_CREDENTIALS needs explicit YO_API_CORS_ALLOW_ORIGINS, not `*`")
+	}
+	for _, origin := range corsAllowOrigins() {
+		if scheme, host, _ := str.Cut(origin, "://"); (origin != "") && (origin != "*") && ((scheme == "") || (host == "") || str.Has(host, "/") || str.Has(host[1:], "*")) {
+			panic("invalid YO_API_CORS_ALLOW_ORIGINS entry: '" + origin + "'")
+		}
+	}
+}
+
+func corsOriginAllowed(origin string) bool {
+	return sl.Any(corsAllowOrigins(), func(it string) bool {
+		if (it == "*") || (it == origin) {
+			return true
+		}
+		scheme, host, _ := str.Cut(it, "://")
+		return str.Begins(host, "*.") && str.Begins(origin, scheme+"://") && str.Ends(origin, host[1:]) &&
+			(len(origin) > len(scheme+"://"+host[1:])) // a sub-domain wildcard does not match the bare domain
+	})
+}
+
+// handleHttpCors adds the CORS response headers according to the `YO_API_CORS_*` config, then returns `true` if
+// `ctx` is a CORS preflight request, which is then already fully answered and not to be handled any further.
+func handleHttpCors(ctx *yoctx.Ctx) (isPreflight bool) {
+	req, resp_headers := ctx.Http.Req, ctx.Http.Resp.Header()
+	resp_headers.Add("Vary", "Origin")
+	origin := req.Header.Get("Origin")
+	if origin == "" {
+		return false
+	}
+	is_allowed := corsOriginAllowed(origin)
+	if is_allowed {
+		resp_headers.Set("Access-Control-Allow-Origin", If(sl.Has(corsAllowOrigins(), "*"), "*", origin))
+		if Cfg.YO_API_CORS_ALLOW_CREDENTIALS {
+			resp_headers.Set("Access-Control-Allow-Credentials", "true")
+		}
+	}
+
+	if isPreflight = (req.Method == http.MethodOptions) && (req.Header.Get("Access-Control-Request-Method") != ""); isPreflight {
+		if is_allowed {
+			resp_headers.Set("Access-Control-Allow-Methods", "GET, HEAD, POST")
+			resp_headers.Set("Access-Control-Allow-Headers", str.Join(If(len(Cfg.YO_API_CORS_ALLOW_HEADERS) == 0, []string{"Content-Type"}, Cfg.YO_API_CORS_ALLOW_HEADERS), ", "))
+			if Cfg.YO_API_CORS_MAX_AGE > 0 {
+				resp_headers.Set("Access-Control-Max-Age", str.FromI64(int64(Cfg.YO_API_CORS_MAX_AGE.Seconds()), 10))
+			}
+		}
+		ctx.TimingsNoPrintInDevMode = true
+		ctx.Http.Resp.WriteHeader(http.StatusNoContent)
+	} else if is_allowed {
+		resp_headers.Set("Access-Control-Expose-Headers", str.Join(corsExposeHeaders, ", "))
+	}
+	return
+}
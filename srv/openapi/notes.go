@@ -49,6 +49,7 @@ About **error responses**:
 - All the well-known (thrown rather than caught) errors listed here:
   - have their code-identifier-compatible (spaceless ASCII) enumerant-name as their entire text response, making all error responses inherently ´switch/case´able;
   - have been recursively determined by code-path walking. Among them are some that logically could not possibly ever occur for that operation, yet identifying those (to filter them out of the listing) is (so far) out of scope for our current ´{spec_file_name}´ spec generator. (In case of serious need, do let us know!)
+- ´429´ ´TooManyRequests´ responses (where listed) come with a ´Retry-After´ response header indicating the number of seconds to wait before retrying.
 - Any non-known (caught rather than thrown) errors (not listed here) contain their original (usually human-language) error message fully, corresponding to the ´default´ in an error-handling ´switch/case´.
 - **"Not Found" rules:**
   - ´404´ **only** for HTTP requests with definitely-unroutable URL paths (ie. "no such API operation or static-file asset or sub-site or etc."),
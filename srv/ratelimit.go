@@ -0,0 +1,118 @@
+package yosrv
+
+import (
+	"math"
+	"net"
+	"sync"
+	"time"
+
+	. "yo/ctx"
+	. "yo/util"
+	"yo/util/sl"
+	"yo/util/str"
+)
+
+// RateLimit allows (per distinct `Key`) at most `Max` requests `Per` duration, via token buckets (so bursts of up to `Max` are fine).
+type RateLimit struct {
+	Key func(*Ctx) string // `RateLimitByIp` if `nil`. Requests with an empty key are not limited.
+	Max int
+	Per time.Duration
+}
+
+var (
+	// RateLimitByIp keys by the client IP: that's the (right-most) `X-Forwarded-For` one only
+	// if the connecting peer is loopback or private-network (ie. a reverse proxy), otherwise the peer's.
+	RateLimitByIp = func(ctx *Ctx) string {
+		ip := ctx.Http.Req.RemoteAddr
+		if host, _, err := net.SplitHostPort(ip); err == nil {
+			ip = host
+		}
+		if peer := net.ParseIP(ip); (peer != nil) && (peer.IsLoopback() || peer.IsPrivate()) {
+			if fwd_for := str.Split(ctx.Http.Req.Header.Get("X-Forwarded-For"), ","); str.Trim(fwd_for[len(fwd_for)-1]) != "" {
+				ip = str.Trim(fwd_for[len(fwd_for)-1])
+			}
+		}
+		return ip
+	}
+	// RateLimitByMethod keys by the request URL path, so that (as a default of `RateLimiting`) each `ApiMethod` has its own limit for all clients combined.
+	RateLimitByMethod = func(ctx *Ctx) string { return ctx.Http.UrlPath }
+)
+
+type rateLimiter struct {
+	RateLimit
+	mut       sync.Mutex
+	buckets   map[string]*rateLimitBucket
+	lastSweep time.Time
+}
+
+type rateLimitBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiters(rateLimits []RateLimit) []*rateLimiter {
+	return sl.As(rateLimits, func(it RateLimit) *rateLimiter {
+		if (it.Max <= 0) || (it.Per <= 0) {
+			panic(str.Fmt("invalid RateLimit: %#v", it))
+		}
+		return &rateLimiter{RateLimit: it, buckets: map[string]*rateLimitBucket{}, lastSweep: time.Now()}
+	})
+}
+
+// take consumes a token from `key`'s bucket if any are left, else returns how long until there will be one.
+func (me *rateLimiter) take(key string) (retryAfter time.Duration) {
+	now, tokens_per_sec := time.Now(), float64(me.Max)/me.Per.Seconds()
+	me.mut.Lock()
+	defer me.mut.Unlock()
+	if now.Sub(me.lastSweep) > me.Per { // buckets idle for `Per` are full anyway, so no need to keep them around
+		for bucket_key, bucket := range me.buckets {
+			if now.Sub(bucket.last) > me.Per {
+				delete(me.buckets, bucket_key)
+			}
+		}
+		me.lastSweep = now
+	}
+
+	bucket := me.buckets[key]
+	if bucket == nil {
+		bucket = &rateLimitBucket{tokens: float64(me.Max), last: now}
+		me.buckets[key] = bucket
+	}
+	bucket.tokens, bucket.last = min(float64(me.Max), bucket.tokens+(now.Sub(bucket.last).Seconds()*tokens_per_sec)), now
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return 0
+	}
+	return time.Duration(((1 - bucket.tokens) / tokens_per_sec) * float64(time.Second))
+}
+
+// rateLimitCheck panics with `ErrTooManyRequests` (and sets the `Retry-After` response header) if any of `rateLimiters` is exceeded.
+func rateLimitCheck(ctx *Ctx, rateLimiters []*rateLimiter) {
+	for _, rate_limiter := range rateLimiters {
+		key := If(rate_limiter.Key == nil, RateLimitByIp, rate_limiter.Key)(ctx)
+		if key == "" {
+			continue
+		}
+		if retry_after := rate_limiter.take(key); retry_after > 0 {
+			ctx.Http.Resp.Header().Set("Retry-After", str.FromInt(int(math.Ceil(retry_after.Seconds()))))
+			ctx.ErrNoNotify = true // no `ErrEntry` DB writes for each rejected request of a flood
+			panic(ErrTooManyRequests)
+		}
+	}
+}
+
+// RateLimiting returns a `Middleware` (for `PreServes` or `PreApiHandling`) enforcing `defaultRateLimits` for all requests
+// other than those to `ApiMethod`s with their own `ApiMethod.RateLimited` overrides. To key by a `RateLimitByAccount` or
+// similar relying on `PreServes` before it, add it after those. It must be called before `yo.Init` for correct API docs.
+func RateLimiting(defaultRateLimits ...RateLimit) Middleware {
+	rate_limiters := newRateLimiters(defaultRateLimits)
+	if len(rate_limiters) > 0 {
+		KnownErrSets[""] = sl.With(KnownErrSets[""], ErrTooManyRequests)
+	}
+	return Middleware{Name: "rateLimit", Do: func(ctx *Ctx) {
+		if api_method := api[ctx.Http.UrlPath]; (api_method != nil) && (len(api_method.rateLimiters()) > 0) {
+			return // checked in `apiHandleRequest`
+		}
+		rateLimitCheck(ctx, rate_limiters)
+	}}
+}
@@ -2,12 +2,18 @@ package yosrv
 
 import (
 	"bytes"
+	"context"
 	"crypto/subtle"
+	"errors"
 	"io"
 	"io/fs"
 	"net/http"
 	"os"
+	"os/signal"
 	"reflect"
+	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
 
 	. "yo/cfg"
@@ -24,6 +30,8 @@ const QueryArgForceFail = "yoFail"
 const QueryArgForceUser = "yoUser"
 const StaticFilesDirName_Yo = "__yostatic"
 const StaticFilesDirName_App = "__static"
+const HealthzUrlPath = yoAdminApisUrlPrefix + "healthz"
+const ReadyzUrlPath = yoAdminApisUrlPrefix + "readyz"
 
 var StaticFileDir_Yo fs.FS
 var StaticFileDir_App fs.FS
@@ -53,10 +61,16 @@ var (
 	AppSideStaticRePathFor func(string) string
 
 	OnBeforeServingStaticFile = func(*yoctx.Ctx) {}
+
+	// OnShutdown funcs are run concurrently with the draining of in-flight requests on SIGTERM/SIGINT, and should return by `drainDeadline`
+	OnShutdown = []func(drainDeadline time.Time){}
+
+	shuttingDown atomic.Bool
 )
 
 func InitAndMaybeCodegen(dbStructs []reflect.Type) func() {
 	apiReflAllDbStructs = dbStructs
+	corsCheckCfg()
 	for dir_name, dir_path := range Cfg.STATIC_FILE_STORAGE_DIRS {
 		StaticFileDirs[dir_name] = os.DirFS(dir_path)
 	}
@@ -75,15 +89,71 @@ func listenAndServe() {
 	if !IsDevMode {
 		PreServes = append(PreServes, Middleware{"authAdmin", If(IsDevMode, nil, authAdmin)})
 	}
+	servers := httpServers()
+	shut_down := make(chan None)
+	go func() {
+		defer close(shut_down)
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGTERM, syscall.SIGINT)
+		yolog.Println("%s: shutting down...", <-signals)
+		shutDown(servers)
+		yolog.Println("shut down")
+	}()
+
 	yolog.Println("live @ port %d", Cfg.YO_API_HTTP_PORT)
+	if len(servers) > 1 {
+		yolog.Println("live @ port %d (TLS)", Cfg.YO_API_HTTPS_PORT)
+	}
 	yoctx.IsUp = true
-	panic(http.ListenAndServe(":"+str.FromInt(Cfg.YO_API_HTTP_PORT), http.HandlerFunc(handleHttpRequest)))
+	var serving sync.WaitGroup
+	for _, server := range servers {
+		serving.Add(1)
+		go func() {
+			defer serving.Done()
+			if err := If(server.TLSConfig == nil, server.ListenAndServe, func() error { return server.ListenAndServeTLS("", "") })(); !errors.Is(err, http.ErrServerClosed) {
+				panic(err)
+			}
+		}()
+	}
+	serving.Wait()
+	<-shut_down
+}
+
+// shutDown stops accepting new connections and lets in-flight requests (and all `OnShutdown`s) finish within `YO_API_SHUTDOWN_DRAIN_TIMEOUT`.
+func shutDown(servers []*http.Server) {
+	shuttingDown.Store(true)
+	drain_deadline := time.Now().Add(If(Cfg.YO_API_SHUTDOWN_DRAIN_TIMEOUT > 0, Cfg.YO_API_SHUTDOWN_DRAIN_TIMEOUT, 22*time.Second))
+	var wait sync.WaitGroup
+	for _, on_shutdown := range OnShutdown {
+		wait.Add(1)
+		go func(onShutdown func(time.Time)) {
+			defer wait.Done()
+			onShutdown(drain_deadline)
+		}(on_shutdown)
+	}
+	ctx, done := context.WithDeadline(context.Background(), drain_deadline)
+	defer done()
+	for _, server := range servers {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			if err := server.Shutdown(ctx); err != nil {
+				yolog.Println("shutdown: %s", err)
+				_ = server.Close()
+			}
+		}()
+	}
+	wait.Wait()
 }
 
 func handleHttpRequest(rw http.ResponseWriter, req *http.Request) {
 	ctx := yoctx.NewCtxForHttp(req, rw, Cfg.YO_API_IMPL_TIMEOUT, false)
 	defer ctx.OnDone(nil)
 
+	if handleHttpHealthCheckMaybe(ctx) || handleHttpCors(ctx) {
+		return
+	}
+
 	if IsDevMode {
 		if s := ctx.GetStr(QueryArgForceFail); s != "" {
 			code, _ := str.ToInt(s)
@@ -113,7 +183,7 @@ func handleHttpRequest(rw http.ResponseWriter, req *http.Request) {
 		}
 	}
 
-	if result, handler_called := apiHandleRequest(ctx); handler_called { // if not, `apiHandleRequest` did `http.Error()` and nothing more to do here
+	if result, handler_called := apiHandleRequest(ctx); handler_called && !ctx.HttpRespWriting() { // if not called, `apiHandleRequest` did `http.Error()`. if writing, the handler streams the response itself
 		ctx.Timings.Step("sani resp")
 		ReflWalk(reflect.ValueOf(result), nil, true, true, true, func(path []any, it reflect.Value) {
 			if (it.Kind() == reflect.Map) && (0 == it.Len()) {
@@ -134,6 +204,7 @@ func handleHttpRequest(rw http.ResponseWriter, req *http.Request) {
 		for k, v := range apisStdRespHeaders {
 			rw.Header().Set(k, v)
 		}
+		resp_data = apiRespMaybeGzip(ctx, resp_data)
 		rw.Header().Set("Content-Length", str.FromInt(len(resp_data)))
 		ctx.HttpOnPreWriteResponse()
 		_, _ = rw.Write(resp_data)
@@ -199,12 +270,44 @@ func handleHttpStaticFileRequestMaybe(ctx *yoctx.Ctx) bool {
 		}
 
 		ctx.HttpOnPreWriteResponse()
-		httpFileServer(fs_handler).ServeHTTP(ctx.Http.Resp, ctx.Http.Req)
+		if IsDevMode || !httpServeStaticFile(ctx, fs_strip_name, fs_static, str.TrimPref(ctx.Http.UrlPath, fs_strip_name+"/")) {
+			httpFileServer(fs_handler).ServeHTTP(ctx.Http.Resp, ctx.Http.Req)
+		}
 		return true
 	}
 	return false
 }
 
+// handleHttpHealthCheckMaybe serves the liveness and readiness probes, prior to (and unaffected by) any `PreServes` such as `authAdmin`.
+// Readiness means: `Init` (including DB migrations) is done, the DB is reachable and no shutdown is under way.
+func handleHttpHealthCheckMaybe(ctx *yoctx.Ctx) bool {
+	if (ctx.Http.UrlPath != HealthzUrlPath) && (ctx.Http.UrlPath != ReadyzUrlPath) {
+		return false
+	}
+	ctx.TimingsNoPrintInDevMode = true
+	if ctx.Http.UrlPath == ReadyzUrlPath {
+		var not_ready string
+		switch {
+		case !yoctx.IsUp:
+			not_ready = "starting up"
+		case shuttingDown.Load():
+			not_ready = "shutting down"
+		case yoctx.DB != nil:
+			if err := yoctx.DB.PingContext(ctx); err != nil {
+				not_ready = "DB unreachable: " + err.Error()
+			}
+		}
+		if not_ready != "" {
+			http.Error(ctx.Http.Resp, not_ready, http.StatusServiceUnavailable)
+			return true
+		}
+	}
+	ctx.Http.Resp.Header().Set("Content-Type", yoctx.MimeTypePlainText)
+	ctx.Http.Resp.Header().Set("Cache-Control", "no-store")
+	_, _ = ctx.Http.Resp.Write([]byte("ok"))
+	return true
+}
+
 func authAdmin(ctx *yoctx.Ctx) {
 	if (!(str.Begins(ctx.Http.UrlPath, yoAdminApisUrlPrefix) || str.Begins(ctx.Http.UrlPath, StaticFilesDirName_Yo+"/yo."))) || (ctx.Http.UrlPath == (yoAdminApisUrlPrefix + "refl")) {
 		return
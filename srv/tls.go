@@ -0,0 +1,121 @@
+package yosrv
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	. "yo/cfg"
+	yolog "yo/log"
+	. "yo/util"
+	"yo/util/str"
+)
+
+const tlsCertReloadCheckInterval = 11 * time.Second
+
+// tlsCertReloader serves the `YO_API_TLS_CERT_FILE` and `YO_API_TLS_KEY_FILE` key pair, re-loading it
+// whenever either file changes (eg. on certbot renewals) without needing a restart. On a failed
+// re-load (eg. caught between the writing of both files), the previous key pair stays in use.
+type tlsCertReloader struct {
+	mut       sync.RWMutex
+	cert      *tls.Certificate
+	certFile  string
+	keyFile   string
+	modTimes  [2]time.Time
+	lastCheck time.Time
+}
+
+func newTlsCertReloader(certFile string, keyFile string) *tlsCertReloader {
+	me := &tlsCertReloader{certFile: certFile, keyFile: keyFile}
+	if err := me.reloadIfChanged(); err != nil {
+		panic(err)
+	}
+	return me
+}
+
+func (me *tlsCertReloader) reloadIfChanged() error {
+	var mod_times [2]time.Time
+	for i, file_path := range []string{me.certFile, me.keyFile} {
+		file_info, err := os.Stat(file_path)
+		if err != nil {
+			return err
+		}
+		mod_times[i] = file_info.ModTime()
+	}
+	me.mut.RLock()
+	unchanged := (me.cert != nil) && (mod_times == me.modTimes)
+	me.mut.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(me.certFile, me.keyFile)
+	if err != nil {
+		return err
+	}
+	me.mut.Lock()
+	me.cert, me.modTimes = &cert, mod_times
+	me.mut.Unlock()
+	return nil
+}
+
+// getCertificate is for `tls.Config.GetCertificate`
+func (me *tlsCertReloader) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	me.mut.Lock()
+	do_check := time.Since(me.lastCheck) >= tlsCertReloadCheckInterval
+	if do_check {
+		me.lastCheck = time.Now()
+	}
+	me.mut.Unlock()
+	if do_check {
+		if err := me.reloadIfChanged(); err != nil {
+			yolog.Println("TLS cert reload: %s", err)
+		}
+	}
+	me.mut.RLock()
+	defer me.mut.RUnlock()
+	return me.cert, nil
+}
+
+// httpServers returns the plain-HTTP server on `YO_API_HTTP_PORT` and, if `YO_API_TLS_CERT_FILE` is set, the HTTPS one on `YO_API_HTTPS_PORT`.
+// With TLS, the plain-HTTP one only redirects to HTTPS if `YO_API_HTTP_REDIRECT_TO_HTTPS` (except for the health checks).
+// HTTP/2 is always on for HTTPS, and on for plain-HTTP (as h2c, for behind proxies) if `YO_API_HTTP2_H2C`.
+func httpServers() (ret []*http.Server) {
+	handler := http.Handler(http.HandlerFunc(handleHttpRequest))
+	server_http := &http.Server{Addr: ":" + str.FromInt(Cfg.YO_API_HTTP_PORT), Handler: handler, Protocols: new(http.Protocols)}
+	server_http.Protocols.SetHTTP1(true)
+	server_http.Protocols.SetUnencryptedHTTP2(Cfg.YO_API_HTTP2_H2C)
+	ret = append(ret, server_http)
+
+	if (Cfg.YO_API_TLS_CERT_FILE != "") || (Cfg.YO_API_TLS_KEY_FILE != "") {
+		if (Cfg.YO_API_TLS_CERT_FILE == "") || (Cfg.YO_API_TLS_KEY_FILE == "") || (Cfg.YO_API_HTTPS_PORT <= 0) {
+			panic("TLS needs all of YO_API_TLS_CERT_FILE, YO_API_TLS_KEY_FILE and YO_API_HTTPS_PORT")
+		}
+		cert_reloader := newTlsCertReloader(Cfg.YO_API_TLS_CERT_FILE, Cfg.YO_API_TLS_KEY_FILE)
+		server_https := &http.Server{Addr: ":" + str.FromInt(Cfg.YO_API_HTTPS_PORT), Handler: handler, Protocols: new(http.Protocols),
+			TLSConfig: &tls.Config{MinVersion: tls.VersionTLS12, GetCertificate: cert_reloader.getCertificate}}
+		server_https.Protocols.SetHTTP1(true)
+		server_https.Protocols.SetHTTP2(true)
+		ret = append(ret, server_https)
+		if Cfg.YO_API_HTTP_REDIRECT_TO_HTTPS {
+			server_http.Handler = http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				if url_path := str.TrimSuff(str.TrimPref(req.URL.Path, "/"), "/"); (url_path == HealthzUrlPath) || (url_path == ReadyzUrlPath) {
+					handler.ServeHTTP(rw, req)
+					return
+				}
+				host, _, err := net.SplitHostPort(req.Host)
+				if err != nil {
+					host = req.Host
+				}
+				if Cfg.YO_API_HTTPS_PORT != 443 {
+					host = net.JoinHostPort(host, str.FromInt(Cfg.YO_API_HTTPS_PORT))
+				}
+				http.Redirect(rw, req, "https://"+host+req.URL.RequestURI(), If(req.Method == http.MethodGet || req.Method == http.MethodHead, http.StatusMovedPermanently, http.StatusPermanentRedirect))
+			})
+		}
+	}
+	return
+}
@@ -5,25 +5,27 @@ import "yo/util/str"
 type Err string
 
 var errSubstrToHttpStatusCode = map[string]int{
-	"WrongPassword": 401,
-	"MustBeAdmin":   401,
-	"Unauthorized":  403,
-	"Forbidden":     403,
-	"DoesNotExist":  406, // no 404 wanted for those, that's for no-such-api-method-or-static-file-or-subsite only
-	"Unacceptable":  406,
-	"AlreadyExists": 409,
-	"Required":      422,
-	"Expected":      422,
-	"Invalid":       422,
-	"TooShort":      422,
-	"TooLong":       422,
-	"TooLow":        422,
-	"TooHigh":       422,
-	"TooSmall":      422,
-	"TooBig":        422,
-	"NotStored":     502,
-	"Timeout":       504,
-	"TimedOut":      504,
+	"WrongPassword":   401,
+	"MustBeAdmin":     401,
+	"Unauthorized":    403,
+	"Forbidden":       403,
+	"DoesNotExist":    406, // no 404 wanted for those, that's for no-such-api-method-or-static-file-or-subsite only
+	"Unacceptable":    406,
+	"AlreadyExists":   409,
+	"Conflict":        409,
+	"Required":        422,
+	"Expected":        422,
+	"Invalid":         422,
+	"TooShort":        422,
+	"TooLong":         422,
+	"TooLow":          422,
+	"TooHigh":         422,
+	"TooSmall":        422,
+	"TooBig":          422,
+	"TooManyRequests": 429,
+	"NotStored":       502,
+	"Timeout":         504,
+	"TimedOut":        504,
 }
 
 func (me Err) Error() string  { return string(me) }
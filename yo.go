@@ -60,8 +60,10 @@ func Init(staticFileDirYo fs.FS, staticFileDirApp fs.FS) (listenAndServe func())
 		yodb.Upsert[yojobs.JobDef](ctx, &yoauth.UserPwdReqJobDef)
 		yodb.Upsert[yojobs.JobDef](ctx, &yomail.MailReqJobDef)
 		yodb.Upsert[yojobs.JobDef](ctx, &errJobDef)
+		yodb.Upsert[yojobs.JobDef](ctx, &yojobs.SoftDelPurgeJobDef)
 		yojobs.Init(ctx) // some db clean-ups in there, doesn't `Engine.Resume` though, that's below
 
+		yosrv.OnShutdown = append(yosrv.OnShutdown, yojobs.Default.Suspend)
 		listen_and_serve := listenAndServe
 		listenAndServe = func() {
 			go yojobs.Default.Resume()
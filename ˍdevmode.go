@@ -4,6 +4,7 @@ package yo
 
 import (
 	"bytes"
+	"compress/gzip"
 	"io/fs"
 	"os"
 	"os/exec"
@@ -191,6 +192,11 @@ use ./`+app_name+`
 		}
 	}
 
+	// 5b. precompressed sidecars of static files, for `yosrv` to serve to accepting clients
+	for _, dir_name := range []string{yosrv.StaticFilesDirName_Yo, yosrv.StaticFilesDirName_App} {
+		staticFilesPrecompress(filepath.Join(dst_dir_path, app_name, dir_name))
+	}
+
 	// 6. go build
 	yolog.Println("BUILD: go build...")
 	cmd_go := exec.Command("go", "build",
@@ -223,6 +229,36 @@ use ./`+app_name+`
 	}
 }
 
+var staticFilesPrecompressExts = []string{".css", ".html", ".js", ".json", ".map", ".md", ".svg", ".ts", ".txt", ".xml"}
+
+// staticFilesPrecompress writes `.gz` (and, if the `brotli` CLI is installed, `.br`) sidecars of all text-ish files in `dirPath` of 1KB or more.
+func staticFilesPrecompress(dirPath string) {
+	if !FsIsDir(dirPath) {
+		return
+	}
+	brotli_cmd_path, _ := exec.LookPath("brotli")
+	if brotli_cmd_path == "" {
+		yolog.Println("BUILD: no `brotli` CLI found, so no .br sidecars in %s", dirPath)
+	}
+	FsDirWalk(dirPath, func(fsPath string, fsEntry fs.DirEntry) {
+		if fsEntry.IsDir() || !sl.Any(staticFilesPrecompressExts, func(ext string) bool { return str.Ends(fsPath, ext) }) {
+			return
+		}
+		data := FsRead(fsPath)
+		if len(data) < 1024 {
+			return
+		}
+		if data_gz := yosrv.Gzipped(data, gzip.BestCompression); len(data_gz) < len(data) {
+			FsWrite(fsPath+".gz", data_gz)
+		}
+		if brotli_cmd_path != "" {
+			if cmd_out, err := exec.Command(brotli_cmd_path, "--best", "--force", "--keep", "--output="+fsPath+".br", fsPath).CombinedOutput(); err != nil {
+				panic(str.Fmt("%s>>>>%s", err, cmd_out))
+			}
+		}
+	})
+}
+
 func cssMinify(srcCss []byte) []byte {
 	is_ascii_nonspace_whitespace, is_sep, is_brace_or_paren := func(c byte) bool { return (c == '\n') || (c == '\t') }, func(c byte) bool { return (c == ':') || (c == ';') || (c == ',') }, func(c byte) bool {
 		return (c == '{') || (c == '}') || (c == '[') || (c == ']') || (c == '(') // || (c == ')') // keep the closing-paren out, generates buggy css in situations like `var(--foo) calc(...)`
@@ -17,3 +17,14 @@ var yoPkg = apiPkgInfo{}
 func api[TIn any, TOut any](f func(*yosrv.ApiCtx[TIn, TOut]), failIfs ...yosrv.Fails) yosrv.ApiMethod {
 	return yosrv.Api[TIn, TOut](f, failIfs...).From(yoPkg)
 }
+
+const ErrDbNotNull_ErrEntry_CtxVals util.Err = "DbNotNull_ErrEntry_CtxVals"
+const ErrDbNotNull_ErrEntry_DbStats util.Err = "DbNotNull_ErrEntry_DbStats"
+const ErrDbNotNull_ErrEntry_DbTx util.Err = "DbNotNull_ErrEntry_DbTx"
+const ErrDbNotNull_ErrEntry_Err util.Err = "DbNotNull_ErrEntry_Err"
+const ErrDbNotNull_ErrEntry_HttpFullUri util.Err = "DbNotNull_ErrEntry_HttpFullUri"
+const ErrDbNotNull_ErrEntry_HttpUrlPath util.Err = "DbNotNull_ErrEntry_HttpUrlPath"
+const ErrDbNotNull_ErrEntry_JobRunId util.Err = "DbNotNull_ErrEntry_JobRunId"
+const ErrDbNotNull_ErrEntry_JobTaskId util.Err = "DbNotNull_ErrEntry_JobTaskId"
+const ErrDbNotNull_ErrEntry_NumCaught util.Err = "DbNotNull_ErrEntry_NumCaught"
+const ErrDbNotNull_ErrEntry_StackTrace util.Err = "DbNotNull_ErrEntry_StackTrace"
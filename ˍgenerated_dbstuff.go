@@ -22,6 +22,7 @@ const (
 	ErrEntryJobRunId    ErrEntryField = "JobRunId"
 	ErrEntryJobTaskId   ErrEntryField = "JobTaskId"
 	ErrEntryDbTx        ErrEntryField = "DbTx"
+	ErrEntryDbStats     ErrEntryField = "DbStats"
 )
 
 func (me ErrEntryField) ArrLen(a1 ...interface{}) q.Operand { return ((q.F)(me)).ArrLen(a1...) }
@@ -36,6 +37,9 @@ func (me ErrEntryField) GreaterOrEqual(a1 interface{}) q.Query { return ((q.F)(m
 func (me ErrEntryField) GreaterThan(a1 interface{}) q.Query    { return ((q.F)(me)).GreaterThan(a1) }
 func (me ErrEntryField) In(a1 ...interface{}) q.Query          { return ((q.F)(me)).In(a1...) }
 func (me ErrEntryField) InArr(a1 interface{}) q.Query          { return ((q.F)(me)).InArr(a1) }
+func (me ErrEntryField) JsonContains(a1 interface{}) q.Query   { return ((q.F)(me)).JsonContains(a1) }
+func (me ErrEntryField) JsonHasKey(a1 string) q.Query          { return ((q.F)(me)).JsonHasKey(a1) }
+func (me ErrEntryField) JsonPath(a1 ...interface{}) q.Operand  { return ((q.F)(me)).JsonPath(a1...) }
 func (me ErrEntryField) LessOrEqual(a1 interface{}) q.Query    { return ((q.F)(me)).LessOrEqual(a1) }
 func (me ErrEntryField) LessThan(a1 interface{}) q.Query       { return ((q.F)(me)).LessThan(a1) }
 func (me ErrEntryField) Not() q.Query                          { return ((q.F)(me)).Not() }